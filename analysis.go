@@ -0,0 +1,423 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/openfluke/paragon/v3"
+)
+
+// SizePoint ties one model's size to its accuracy and speed — one dot on a
+// "does bigger help on MNIST?" scatter plot.
+type SizePoint struct {
+	Model       string  `json:"model"`
+	Params      int64   `json:"params"`
+	Bytes       int64   `json:"bytes"`
+	TestScore   float64 `json:"test_adhd_score"`
+	MeanInferMS float64 `json:"mean_infer_ms"` // CPU forward, averaged over the test split
+	TestSamples int     `json:"test_samples"`
+}
+
+// exportSizeVsAccuracy evaluates every model in public/models on the test
+// split (CPU, so timings are comparable across machines with/without GPU)
+// and writes public/analysis/size_vs_accuracy.{json,csv}.
+func exportSizeVsAccuracy() {
+	modelDir := ModelsDir()
+
+	images, labels, err := getMNIST()
+	if err != nil {
+		fmt.Println("❌ Failed to load MNIST:", err)
+		return
+	}
+	_, _, testInputs, testTargets := splitMNIST(images, labels)
+
+	models, err := listModels(modelDir)
+	if err != nil {
+		fmt.Println("❌ Failed to read models dir:", err)
+		return
+	}
+
+	var points []SizePoint
+	for _, name := range models {
+		modelPath := filepath.Join(modelDir, name)
+		fmt.Printf("📦 %s …\n", name)
+
+		nn, err := loadFloat32Model(modelPath)
+		if err != nil {
+			fmt.Printf("   ⚠️ %v\n", err)
+			continue
+		}
+		nn.WebGPUNative = false
+
+		start := time.Now()
+		score := evalADHDScore(nn, testInputs, testTargets)
+		elapsed := time.Since(start)
+
+		var size int64
+		if fi, err := os.Stat(modelPath); err == nil {
+			size = fi.Size()
+		}
+		p := SizePoint{
+			Model:       name,
+			Params:      countParams(nn),
+			Bytes:       size,
+			TestScore:   score,
+			MeanInferMS: safeDiv(float64(elapsed.Microseconds())/1000.0, float64(len(testInputs))),
+			TestSamples: len(testInputs),
+		}
+		points = append(points, p)
+		fmt.Printf("   params=%d score=%.4f%% infer=%.4fms\n", p.Params, p.TestScore, p.MeanInferMS)
+	}
+
+	if len(points) == 0 {
+		fmt.Println("❌ No models evaluated")
+		return
+	}
+
+	outDir, err := EnsurePublicDir("analysis")
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	jsonPath := filepath.Join(outDir, "size_vs_accuracy.json")
+	if err := writeJSON(jsonPath, points); err != nil {
+		fmt.Printf("❌ Failed to write %s: %v\n", jsonPath, err)
+		return
+	}
+	csvPath := filepath.Join(outDir, "size_vs_accuracy.csv")
+	if err := writeSizePointsCSV(csvPath, points); err != nil {
+		fmt.Printf("❌ Failed to write %s: %v\n", csvPath, err)
+		return
+	}
+	fmt.Printf("💾 Written → %s\n💾 Written → %s\n", jsonPath, csvPath)
+}
+
+func writeSizePointsCSV(path string, points []SizePoint) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	_ = w.Write([]string{"model", "params", "bytes", "test_adhd_score", "mean_infer_ms", "test_samples"})
+	for _, p := range points {
+		_ = w.Write([]string{
+			p.Model,
+			strconv.FormatInt(p.Params, 10),
+			strconv.FormatInt(p.Bytes, 10),
+			strconv.FormatFloat(p.TestScore, 'f', 4, 64),
+			strconv.FormatFloat(p.MeanInferMS, 'f', 6, 64),
+			strconv.Itoa(p.TestSamples),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// DeviceBenchRow is one model's GPU init + CPU/GPU forward numbers on the
+// fixed digit samples — a local ModelRun summary without the host round-trip.
+type DeviceBenchRow struct {
+	Model       string  `json:"model"`
+	GPUInitOK   bool    `json:"gpu_init_ok"`
+	GPUFallback bool    `json:"gpu_fallback"` // gpu_* columns are CPU numbers
+	GPUInitMS   float64 `json:"gpu_init_ms"`
+	CPUMeanMS   float64 `json:"cpu_mean_ms"` // mean forward over the digit samples
+	GPUMeanMS   float64 `json:"gpu_mean_ms"` // CPU fallback timings if GPU init failed
+	Speedup     float64 `json:"speedup"`     // cpu_mean / gpu_mean (>1 means GPU is faster)
+	MaxDrift    float64 `json:"max_drift"`
+	Samples     int     `json:"samples"`
+	SampleMode  string  `json:"sample_mode"`
+}
+
+// exportDeviceBench runs every model through runModelTelemetry (same code path
+// as a telemetry client) and writes public/analysis/device_bench.json.
+func exportDeviceBench() {
+	modelDir := ModelsDir()
+
+	images, labels, err := getMNIST()
+	if err != nil {
+		fmt.Println("❌ Failed to load MNIST:", err)
+		return
+	}
+	sel, err := sampleIndexPerDigit(labels)
+	if err != nil {
+		fmt.Println("❌ Sample selection:", err)
+		return
+	}
+
+	models, err := listModels(modelDir)
+	if err != nil {
+		fmt.Println("❌ Failed to read models dir:", err)
+		return
+	}
+
+	var rows []DeviceBenchRow
+	for _, name := range models {
+		fmt.Printf("📦 %s …\n", name)
+		mr, err := runModelTelemetry(filepath.Join(modelDir, name), images, sel, cpuOnly())
+		if err != nil {
+			fmt.Printf("   ⚠️ %v\n", err)
+			continue
+		}
+		rows = append(rows, deviceBenchRow(name, mr))
+	}
+	if len(rows) == 0 {
+		fmt.Println("❌ No models benchmarked")
+		return
+	}
+
+	fmt.Printf("\n%-24s | %-10s | %-11s | %-11s | %-8s | %-10s\n", "Model", "GPU init", "CPU fwd", "GPU fwd", "Speedup", "Max drift")
+	fmt.Println("------------------------------------------------------------------------------------------")
+	for _, r := range rows {
+		initCol := "failed"
+		if r.GPUInitOK {
+			initCol = fmt.Sprintf("%.2fms", r.GPUInitMS)
+		}
+		fmt.Printf("%-24s | %-10s | %-11s | %-11s | %-8s | %-10.2e\n", r.Model, initCol,
+			fmt.Sprintf("%.4fms", r.CPUMeanMS), fmt.Sprintf("%.4fms", r.GPUMeanMS),
+			fmt.Sprintf("%.2fx", r.Speedup), r.MaxDrift)
+	}
+
+	outDir, err := EnsurePublicDir("analysis")
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	jsonPath := filepath.Join(outDir, "device_bench.json")
+	if err := writeJSON(jsonPath, rows); err != nil {
+		fmt.Printf("❌ Failed to write %s: %v\n", jsonPath, err)
+		return
+	}
+	fmt.Printf("💾 Written → %s\n", jsonPath)
+}
+
+func deviceBenchRow(name string, mr ModelRun) DeviceBenchRow {
+	row := DeviceBenchRow{
+		Model:       name,
+		GPUInitOK:   mr.WebGPUInitOK,
+		GPUFallback: mr.GPUFallback,
+		GPUInitMS:   mr.WebGPUInitTimeMS,
+		Samples:     len(mr.CPU),
+		SampleMode:  sampleSelectionLabel(),
+	}
+	var cpuSum, gpuSum float64
+	for _, t := range mr.CPU {
+		cpuSum += t.ElapsedMS
+	}
+	for _, t := range mr.GPU {
+		gpuSum += t.ElapsedMS
+	}
+	row.CPUMeanMS = safeDiv(cpuSum, float64(len(mr.CPU)))
+	row.GPUMeanMS = safeDiv(gpuSum, float64(len(mr.GPU)))
+	row.Speedup = safeDiv(row.CPUMeanMS, row.GPUMeanMS)
+	for _, d := range mr.Drift {
+		if d.MaxAbs > row.MaxDrift {
+			row.MaxDrift = d.MaxAbs
+		}
+	}
+	return row
+}
+
+// LoadBenchRow is how long one model takes to come off disk: the JSON parse
+// in LoadNamedNetworkFromJSONFile and the NewNetwork + Marshal/Unmarshal
+// rebuild every load path goes through.
+type LoadBenchRow struct {
+	Model     string  `json:"model"`
+	Bytes     int64   `json:"bytes"`
+	Params    int64   `json:"params"`
+	ParseMS   float64 `json:"parse_ms"`
+	RebuildMS float64 `json:"rebuild_ms"`
+	TotalMS   float64 `json:"total_ms"`
+	MBPerSec  float64 `json:"mb_per_sec"` // file size / total load time
+}
+
+// exportLoadBench times loading every model in public/models and writes
+// public/analysis/load_bench.json.
+func exportLoadBench() {
+	modelDir := ModelsDir()
+	models, err := listModels(modelDir)
+	if err != nil {
+		fmt.Println("❌ Failed to read models dir:", err)
+		return
+	}
+
+	var rows []LoadBenchRow
+	for _, name := range models {
+		modelPath := filepath.Join(modelDir, name)
+		var size int64
+		if fi, err := os.Stat(modelPath); err == nil {
+			size = fi.Size()
+		}
+
+		start := time.Now()
+		loaded, err := paragon.LoadNamedNetworkFromJSONFile(modelPath)
+		parse := time.Since(start)
+		if err != nil {
+			fmt.Printf("⚠️  %s: load failed: %v\n", name, err)
+			continue
+		}
+		start = time.Now()
+		nn, err := rebuildFloat32(loaded)
+		rebuild := time.Since(start)
+		if err != nil {
+			fmt.Printf("⚠️  %s: %v\n", name, err)
+			continue
+		}
+
+		total := parse + rebuild
+		rows = append(rows, LoadBenchRow{
+			Model:     name,
+			Bytes:     size,
+			Params:    countParams(nn),
+			ParseMS:   float64(parse.Microseconds()) / 1000.0,
+			RebuildMS: float64(rebuild.Microseconds()) / 1000.0,
+			TotalMS:   float64(total.Microseconds()) / 1000.0,
+			MBPerSec:  safeDiv(float64(size)/(1<<20), total.Seconds()),
+		})
+		recordTiming("load", total)
+	}
+	if len(rows) == 0 {
+		fmt.Println("❌ No models loaded")
+		return
+	}
+
+	fmt.Printf("\n%-24s | %-10s | %-11s | %-11s | %-11s | %-8s\n", "Model", "Size", "Parse", "Rebuild", "Total", "MB/s")
+	fmt.Println("------------------------------------------------------------------------------------------")
+	var sum float64
+	for _, r := range rows {
+		fmt.Printf("%-24s | %-10s | %-11s | %-11s | %-11s | %-8.1f\n", r.Model,
+			fmt.Sprintf("%.1fMB", float64(r.Bytes)/(1<<20)),
+			fmt.Sprintf("%.2fms", r.ParseMS), fmt.Sprintf("%.2fms", r.RebuildMS),
+			fmt.Sprintf("%.2fms", r.TotalMS), r.MBPerSec)
+		sum += r.TotalMS
+	}
+	fmt.Printf("Σ %.2fms to load %d models\n", sum, len(rows))
+
+	outDir, err := EnsurePublicDir("analysis")
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	jsonPath := filepath.Join(outDir, "load_bench.json")
+	if err := writeJSON(jsonPath, rows); err != nil {
+		fmt.Printf("❌ Failed to write %s: %v\n", jsonPath, err)
+		return
+	}
+	fmt.Printf("💾 Written → %s\n", jsonPath)
+}
+
+// DatasetStats is a pre-training sanity check of the MNIST files: class
+// balance, pixel intensity and blank images. A corrupt or truncated file
+// shows up here instead of as a mysteriously bad score.
+type DatasetStats struct {
+	Dir        string   `json:"dir"`
+	Samples    int      `json:"samples"`
+	Train      int      `json:"train"` // from the train-* files; the rest are t10k
+	Classes    int      `json:"classes"`
+	PerClass   []int    `json:"per_class"`
+	PixelMean  float64  `json:"pixel_mean"` // over all pixels, 0..1
+	PixelStd   float64  `json:"pixel_std"`
+	AllBlack   int      `json:"all_black"`              // images with every pixel 0
+	AllBlackAt []int    `json:"all_black_at,omitempty"` // first few indices, for a look
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+// maxBlackIndices caps DatasetStats.AllBlackAt.
+const maxBlackIndices = 20
+
+// datasetStats loads the MNIST files in dir (honouring --max-samples) and
+// computes DatasetStats.
+func datasetStats(dir string) (DatasetStats, error) {
+	images, labels, nTrain, err := loadMNISTCombinedCtx(context.Background(), dir)
+	if err != nil {
+		return DatasetStats{}, wrapMNISTErr(err)
+	}
+	st := DatasetStats{Dir: dir, Samples: len(images), Train: nTrain, Classes: oneHotWidth(labels)}
+	st.PerClass = make([]int, st.Classes)
+
+	var sum, sumSq float64
+	var pixels int
+	for i, img := range images {
+		black := true
+		for _, row := range img {
+			for _, v := range row {
+				sum += v
+				sumSq += v * v
+				if v != 0 {
+					black = false
+				}
+			}
+			pixels += len(row)
+		}
+		if black {
+			st.AllBlack++
+			if len(st.AllBlackAt) < maxBlackIndices {
+				st.AllBlackAt = append(st.AllBlackAt, i)
+			}
+		}
+		if c := argmax64(labels[i][0]); c >= 0 && c < st.Classes {
+			st.PerClass[c]++
+		}
+	}
+	st.PixelMean = safeDiv(sum, float64(pixels))
+	st.PixelStd = math.Sqrt(math.Max(0, safeDiv(sumSq, float64(pixels))-st.PixelMean*st.PixelMean))
+
+	if st.AllBlack > 0 {
+		st.Warnings = append(st.Warnings, fmt.Sprintf("%d all-black image(s) — a truncated or corrupt images file looks like this", st.AllBlack))
+	}
+	minC, maxC := -1, 0
+	for c, n := range st.PerClass {
+		if n == 0 {
+			st.Warnings = append(st.Warnings, fmt.Sprintf("class %d has no samples", c))
+		}
+		if minC < 0 || n < minC {
+			minC = n
+		}
+		maxC = max(maxC, n)
+	}
+	if minC > 0 && float64(maxC) > 2*float64(minC) {
+		st.Warnings = append(st.Warnings, fmt.Sprintf("classes are imbalanced (%d vs %d samples) — consider --balance", minC, maxC))
+	}
+	return st, nil
+}
+
+// exportDatasetStats prints datasetStats for the MNIST dir and writes
+// public/analysis/dataset_stats.json.
+func exportDatasetStats() {
+	st, err := datasetStats(MustPublicPath("mnist"))
+	if err != nil {
+		fmt.Println("❌ Failed to load MNIST:", err)
+		return
+	}
+
+	fmt.Printf("📊 %d samples (%d train / %d t10k), %d classes\n", st.Samples, st.Train, st.Samples-st.Train, st.Classes)
+	for c, n := range st.PerClass {
+		fmt.Printf("   %2d: %6d (%.1f%%)\n", c, n, 100*safeDiv(float64(n), float64(st.Samples)))
+	}
+	fmt.Printf("   pixel mean %.4f, std %.4f, all-black images %d\n", st.PixelMean, st.PixelStd, st.AllBlack)
+	for _, w := range st.Warnings {
+		fmt.Println("⚠️ ", w)
+	}
+	if len(st.Warnings) == 0 {
+		fmt.Println("✅ No data problems found")
+	}
+
+	outDir, err := EnsurePublicDir("analysis")
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	jsonPath := filepath.Join(outDir, "dataset_stats.json")
+	if err := writeJSON(jsonPath, st); err != nil {
+		fmt.Printf("❌ Failed to write %s: %v\n", jsonPath, err)
+		return
+	}
+	fmt.Printf("💾 Written → %s\n", jsonPath)
+}
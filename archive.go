@@ -0,0 +1,267 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// loadModelsFromArchive extracts a .zip or .tar.gz/.tgz of models (plus
+// manifest) into a fresh temp dir and returns the directory holding the
+// models — the archive's top-level "models/" folder if it has one, so
+// archives made by exportModelsArchive round-trip. Entries that would land
+// outside the temp dir are skipped.
+func loadModelsFromArchive(path string) (string, error) {
+	tmp, err := os.MkdirTemp("", "paragon-models-*")
+	if err != nil {
+		return "", err
+	}
+
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		err = extractZip(path, tmp)
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		err = extractTarGz(path, tmp)
+	default:
+		err = fmt.Errorf("unsupported archive %q (want .zip, .tar.gz or .tgz)", filepath.Base(path))
+	}
+	if err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", err
+	}
+
+	if sub := filepath.Join(tmp, "models"); isDir(sub) {
+		return sub, nil
+	}
+	return tmp, nil
+}
+
+func extractZip(path, dst string) error {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = extractEntry(dst, f.Name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractTarGz(path, dst string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := extractEntry(dst, h.Name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// extractEntry writes one archive member under dst, refusing absolute or
+// "../" names (zip-slip).
+func extractEntry(dst, name string, r io.Reader) error {
+	name = strings.TrimPrefix(filepath.ToSlash(name), "./")
+	if !safeModelRelPath(name) {
+		fmt.Printf("⚠️  Skipping unsafe archive entry %q\n", name)
+		return nil
+	}
+	out := filepath.Join(dst, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// exportModelsArchive bundles every file under modelDir (models, manifest,
+// status) into out, as zip or tar.gz depending on out's extension. Entries are
+// stored under "models/".
+func exportModelsArchive(modelDir, out string) (int, error) {
+	var files []string
+	err := filepath.WalkDir(modelDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != modelDir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		files = append(files, p)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(files) == 0 {
+		return 0, fmt.Errorf("no files in %s", modelDir)
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	lower := strings.ToLower(out)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		zw := zip.NewWriter(f)
+		for _, p := range files {
+			rel, _ := filepath.Rel(modelDir, p)
+			w, err := zw.Create("models/" + filepath.ToSlash(rel))
+			if err != nil {
+				return 0, err
+			}
+			if err := copyFileTo(w, p); err != nil {
+				return 0, err
+			}
+		}
+		if err := zw.Close(); err != nil {
+			return 0, err
+		}
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		gz := gzip.NewWriter(f)
+		tw := tar.NewWriter(gz)
+		for _, p := range files {
+			fi, err := os.Stat(p)
+			if err != nil {
+				return 0, err
+			}
+			rel, _ := filepath.Rel(modelDir, p)
+			h := &tar.Header{
+				Name:    "models/" + filepath.ToSlash(rel),
+				Mode:    0o644,
+				Size:    fi.Size(),
+				ModTime: fi.ModTime(),
+			}
+			if err := tw.WriteHeader(h); err != nil {
+				return 0, err
+			}
+			if err := copyFileTo(tw, p); err != nil {
+				return 0, err
+			}
+		}
+		if err := tw.Close(); err != nil {
+			return 0, err
+		}
+		if err := gz.Close(); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, fmt.Errorf("unsupported archive %q (want .zip, .tar.gz or .tgz)", filepath.Base(out))
+	}
+	return len(files), nil
+}
+
+func copyFileTo(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+func runArchiveMenu() {
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Println("\nModel archive:")
+	fmt.Println("1) Export the current models dir as .zip / .tar.gz")
+	fmt.Println("2) Import an archive and use it as the models dir for this session")
+	fmt.Println("0) Back")
+	fmt.Print("Select: ")
+	modeRaw, _ := reader.ReadString('\n')
+	switch strings.TrimSpace(modeRaw) {
+	case "1":
+		def := filepath.Join(MustPublicPath("exports"), fmt.Sprintf("models_%d.zip", time.Now().Unix()))
+		fmt.Printf("Output file [default %s]: ", def)
+		outRaw, _ := reader.ReadString('\n')
+		out := strings.TrimSpace(outRaw)
+		if out == "" {
+			out = def
+		}
+		if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+			fmt.Println("❌", err)
+			return
+		}
+		n, err := exportModelsArchive(ModelsDir(), out)
+		if err != nil {
+			fmt.Println("❌ Export failed:", err)
+			return
+		}
+		fmt.Printf("📦 Exported %d file(s) from %s → %s\n", n, ModelsDir(), out)
+	case "2":
+		fmt.Print("Archive path (.zip / .tar.gz): ")
+		inRaw, _ := reader.ReadString('\n')
+		in := strings.TrimSpace(inRaw)
+		if in == "" {
+			fmt.Println("❌ path required")
+			return
+		}
+		dir, err := loadModelsFromArchive(in)
+		if err != nil {
+			fmt.Println("❌ Import failed:", err)
+			return
+		}
+		models, _ := listModels(dir)
+		SetModelsDir(dir)
+		fmt.Printf("📂 Extracted %d model(s) to %s\n", len(models), dir)
+		fmt.Println("✅ Menus now use this directory until exit (or pass --models-dir next time).")
+	case "0":
+		return
+	default:
+		fmt.Println("❌ Invalid choice")
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BatchOp is one entry of a batch file. Only the fields relevant to Op are read:
+//
+//	[
+//	  {"op": "train",    "model": "mnist_S1.json", "epochs": 3, "lr": 0.01},
+//	  {"op": "train",    "model": "mnist_S1.json", "target": 90, "max_epochs": 10},
+//	  {"op": "evaluate", "model": "mnist_S1.json", "cpu_only": true},
+//	  {"op": "compare",  "model": "mnist_S1.json"},
+//	  {"op": "bench",    "duration": "2s", "filter": "floats", "mode": "isolated"}
+//	]
+type BatchOp struct {
+	Op    string `json:"op"`              // train | evaluate | compare | bench
+	Model string `json:"model,omitempty"` // relative to the models dir (public/models by default)
+
+	// train
+	Epochs    int     `json:"epochs,omitempty"`
+	LR        float64 `json:"lr,omitempty"`
+	Smoothing float64 `json:"smoothing,omitempty"`
+	Target    float64 `json:"target,omitempty"`     // > 0 → train until ADHD ≥ target
+	MaxEpochs int     `json:"max_epochs,omitempty"` // cap for target mode
+
+	// evaluate
+	CPUOnly bool `json:"cpu_only,omitempty"`
+
+	// bench
+	Duration string `json:"duration,omitempty"`
+	Filter   string `json:"filter,omitempty"`
+	Mode     string `json:"mode,omitempty"`
+}
+
+// BatchResult records the outcome of one op, in file order.
+type BatchResult struct {
+	Index     int     `json:"index"`
+	Op        string  `json:"op"`
+	Model     string  `json:"model,omitempty"`
+	OK        bool    `json:"ok"`
+	Error     string  `json:"error,omitempty"`
+	ElapsedMS float64 `json:"elapsed_ms"`
+	Result    any     `json:"result,omitempty"`
+}
+
+type BatchReport struct {
+	File      string        `json:"file"`
+	StartedAt time.Time     `json:"started_at"`
+	EndedAt   time.Time     `json:"ended_at"`
+	Results   []BatchResult `json:"results"`
+}
+
+func (b BatchReport) ToJSON() string {
+	bz, _ := json.MarshalIndent(b, "", "  ")
+	return string(bz)
+}
+
+// runBatchFile executes the ops in path in order (a failing op is recorded and
+// the batch continues) and writes the combined report to
+// public/batch/batch_<unix>.json. Invoked as `iso-demo batch ops.json`.
+func runBatchFile(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read batch file: %w", err)
+	}
+	var ops []BatchOp
+	if err := json.Unmarshal(b, &ops); err != nil {
+		return fmt.Errorf("parse batch file %s: %w", path, err)
+	}
+	if len(ops) == 0 {
+		return fmt.Errorf("batch file %s has no ops", path)
+	}
+
+	runCtx := captureRunContext()
+	runCtx.Operation = "batch"
+	report := BatchReport{File: path, StartedAt: time.Now().UTC()}
+	okCount := 0
+	for i, op := range ops {
+		fmt.Printf("\n▶ [%d/%d] %s %s\n", i+1, len(ops), op.Op, op.Model)
+		start := time.Now()
+		res, err := runBatchOp(op)
+		r := BatchResult{
+			Index:     i,
+			Op:        op.Op,
+			Model:     op.Model,
+			OK:        err == nil,
+			ElapsedMS: float64(time.Since(start).Microseconds()) / 1000.0,
+			Result:    res,
+		}
+		if err != nil {
+			r.Error = err.Error()
+			fmt.Printf("❌ [%d/%d] %s: %v\n", i+1, len(ops), op.Op, err)
+		} else {
+			okCount++
+		}
+		report.Results = append(report.Results, r)
+	}
+	report.EndedAt = time.Now().UTC()
+
+	outDir := MustPublicPath("batch")
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return fmt.Errorf("create batch dir: %w", err)
+	}
+	outPath := filepath.Join(outDir, fmt.Sprintf("batch_%d.json", time.Now().Unix()))
+	if err := writeJSON(outPath, report); err != nil {
+		return fmt.Errorf("write batch report: %w", err)
+	}
+	if err := writeRunContext(outPath, runCtx); err != nil {
+		fmt.Printf("⚠️  Could not write run context: %v\n", err)
+	}
+	fmt.Printf("\n✅ Batch done: %d/%d ops succeeded\n💾 Results → %s\n", okCount, len(ops), outPath)
+	return nil
+}
+
+// runBatchOp dispatches one op to the same functions the menu uses.
+func runBatchOp(op BatchOp) (any, error) {
+	switch op.Op {
+	case "train", "evaluate", "compare":
+		if err := ensureMNISTOrGuide(); err != nil {
+			return nil, err
+		}
+	}
+	switch op.Op {
+	case "train":
+		modelPath, err := batchModelPath(op.Model)
+		if err != nil {
+			return nil, err
+		}
+		lr := op.LR
+		if lr <= 0 {
+			lr = 0.01
+		}
+		if op.Target > 0 {
+			maxEpochs := op.MaxEpochs
+			if maxEpochs < 1 {
+				maxEpochs = 10
+			}
+			return nil, trainModelUntilScore(modelPath, op.Target, maxEpochs, lr, op.Smoothing)
+		}
+		epochs := op.Epochs
+		if epochs < 1 {
+			epochs = 1
+		}
+		return nil, trainModelEpochs(modelPath, epochs, lr, op.Smoothing)
+
+	case "evaluate":
+		modelPath, err := batchModelPath(op.Model)
+		if err != nil {
+			return nil, err
+		}
+		res, err := evaluateModelADHD(modelPath, op.CPUOnly || cpuOnly())
+		if err != nil {
+			return nil, err
+		}
+		res.Model = op.Model
+		if err := saveEvalResult(op.Model, res); err != nil {
+			fmt.Printf("⚠️  Could not save eval result for %s: %v\n", op.Model, err)
+		}
+		return res, nil
+
+	case "compare":
+		modelPath, err := batchModelPath(op.Model)
+		if err != nil {
+			return nil, err
+		}
+		res, err := runCompareCPUvsGPU(modelPath, nil)
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+
+	case "bench":
+		dur := 2 * time.Second
+		if op.Duration != "" {
+			d, err := time.ParseDuration(op.Duration)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("invalid duration %q", op.Duration)
+			}
+			dur = d
+		}
+		info, err := CollectBenchmarksMode(dur, op.Filter, op.Mode)
+		if err != nil {
+			return nil, err
+		}
+		return info, nil
+
+	default:
+		return nil, fmt.Errorf("unknown op %q (want train, evaluate, compare or bench)", op.Op)
+	}
+}
+
+// batchModelPath resolves a model name relative to public/models, refusing
+// anything that would escape it.
+func batchModelPath(name string) (string, error) {
+	if !safeModelRelPath(name) {
+		return "", fmt.Errorf("invalid model %q", name)
+	}
+	p := filepath.Join(ModelsDir(), filepath.FromSlash(name))
+	if _, err := os.Stat(p); err != nil {
+		return "", fmt.Errorf("model %s: %w", name, err)
+	}
+	return p, nil
+}
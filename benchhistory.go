@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --bench-history appends every microbench result to
+// public/bench_history/<machine-id>.jsonl, so a fleet can share one directory
+// and the trend command can spot a machine slowly losing throughput.
+var flagBenchHistory = flag.Bool("bench-history", false, "Append each CPU microbench result to public/bench_history/<machine-id>.jsonl")
+
+func benchHistoryEnabled() bool {
+	return flag.Parsed() && *flagBenchHistory
+}
+
+// benchTrendDrop is the fitted loss in throughput over the history's span
+// that counts as degradation, provided the slope is also significant.
+const benchTrendDrop = 0.05
+
+// BenchHistoryEntry is one line of a machine's bench history. Throughput is
+// stored per second so runs with different durations compare directly.
+type BenchHistoryEntry struct {
+	MachineID string                    `json:"machine_id"`
+	StartedAt time.Time                 `json:"started_at"`
+	BenchSec  float64                   `json:"bench_sec"` // requested duration per type
+	RunMode   string                    `json:"run_mode"`
+	Filter    string                    `json:"filter"`
+	Build     BuildInfo                 `json:"build"`
+	OpsPerSec map[string]BenchOpsPerSec `json:"ops_per_sec"` // by type
+}
+
+// BenchOpsPerSec is one type's single- and multi-threaded throughput.
+type BenchOpsPerSec struct {
+	Single float64 `json:"single"`
+	Multi  float64 `json:"multi"`
+}
+
+// BenchTrend is the fitted trend of one type and thread mode.
+type BenchTrend struct {
+	Type        string    `json:"type"`
+	Threads     string    `json:"threads"` // single | multi
+	Runs        int       `json:"runs"`
+	First       time.Time `json:"first"`
+	Last        time.Time `json:"last"`
+	MeanOps     float64   `json:"mean_ops_per_sec"`
+	SlopePerDay float64   `json:"slope_ops_per_sec_per_day"`
+	SlopeStdErr float64   `json:"slope_stderr"`
+	ChangePct   float64   `json:"fitted_change_pct"` // fitted last vs fitted first
+	Degrading   bool      `json:"degrading"`
+}
+
+// BenchTrendReport is what the trend command writes to analysis/bench_trend.json.
+type BenchTrendReport struct {
+	MachineID   string       `json:"machine_id"`
+	GeneratedAt time.Time    `json:"generated_at"`
+	Entries     int          `json:"entries"`
+	Trends      []BenchTrend `json:"trends"`
+}
+
+// benchHistoryPath is the history file for machineID.
+func benchHistoryPath(machineID string) (string, error) {
+	dir, err := EnsurePublicDir("bench_history")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, machineID+".jsonl"), nil
+}
+
+// recordBenchHistory appends b to this machine's history; it does nothing
+// without --bench-history. dur is the per-type duration b was run with.
+func recordBenchHistory(b BenchInfo, dur time.Duration) {
+	if !benchHistoryEnabled() || len(b.Results) == 0 || dur <= 0 {
+		return
+	}
+	e := BenchHistoryEntry{
+		MachineID: hashSystemInfo(b.System),
+		StartedAt: b.StartedAt,
+		BenchSec:  dur.Seconds(),
+		RunMode:   b.RunMode,
+		Filter:    b.Filter,
+		Build:     b.System.Build,
+		OpsPerSec: make(map[string]BenchOpsPerSec, len(b.Results)),
+	}
+	for _, r := range b.Results {
+		e.OpsPerSec[r.Type] = BenchOpsPerSec{Single: float64(r.Single) / dur.Seconds(), Multi: float64(r.Multi) / dur.Seconds()}
+	}
+	if err := appendBenchHistory(e); err != nil {
+		fmt.Printf("⚠️  Could not append bench history: %v\n", err)
+	}
+}
+
+func appendBenchHistory(e BenchHistoryEntry) error {
+	path, err := benchHistoryPath(e.MachineID)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	// one write per line, so concurrent appenders don't interleave
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// loadBenchHistory reads a history file oldest first. Lines that don't parse
+// (e.g. a write cut short) are skipped and counted.
+func loadBenchHistory(path string) ([]BenchHistoryEntry, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+	var out []BenchHistoryEntry
+	bad := 0
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		var e BenchHistoryEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			bad++
+			continue
+		}
+		out = append(out, e)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].StartedAt.Before(out[j].StartedAt) })
+	return out, bad, sc.Err()
+}
+
+// benchTrends fits ops/sec against time for every type and thread mode with
+// at least three runs. A trend is degrading when the fitted throughput fell
+// by benchTrendDrop or more over the span and the slope is more than two
+// standard errors below zero.
+func benchTrends(entries []BenchHistoryEntry) []BenchTrend {
+	type key struct{ typ, threads string }
+	type point struct {
+		at  time.Time
+		ops float64
+	}
+	series := map[key][]point{}
+	for _, e := range entries {
+		for t, o := range e.OpsPerSec {
+			series[key{t, "single"}] = append(series[key{t, "single"}], point{e.StartedAt, o.Single})
+			series[key{t, "multi"}] = append(series[key{t, "multi"}], point{e.StartedAt, o.Multi})
+		}
+	}
+
+	var out []BenchTrend
+	for k, pts := range series {
+		if len(pts) < 3 {
+			continue
+		}
+		xs := make([]float64, len(pts))
+		ys := make([]float64, len(pts))
+		for i, p := range pts {
+			xs[i] = p.at.Sub(pts[0].at).Hours() / 24
+			ys[i] = p.ops
+		}
+		slope, intercept, se := linearFit(xs, ys)
+		mean, _ := meanStdDev(ys)
+		tr := BenchTrend{
+			Type: k.typ, Threads: k.threads, Runs: len(pts),
+			First: pts[0].at, Last: pts[len(pts)-1].at,
+			MeanOps: mean, SlopePerDay: slope, SlopeStdErr: se,
+		}
+		if intercept > 0 {
+			tr.ChangePct = slope * xs[len(xs)-1] / intercept * 100
+		}
+		tr.Degrading = tr.ChangePct <= -benchTrendDrop*100 && slope < -2*se
+		out = append(out, tr)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Type != out[j].Type {
+			return out[i].Type < out[j].Type
+		}
+		return out[i].Threads > out[j].Threads // single before multi
+	})
+	return out
+}
+
+// linearFit is the least-squares line y = intercept + slope·x and the
+// standard error of the slope (0 when it can't be estimated).
+func linearFit(xs, ys []float64) (slope, intercept, stderr float64) {
+	n := float64(len(xs))
+	mx, _ := meanStdDev(xs)
+	my, _ := meanStdDev(ys)
+	var sxx, sxy float64
+	for i := range xs {
+		sxx += (xs[i] - mx) * (xs[i] - mx)
+		sxy += (xs[i] - mx) * (ys[i] - my)
+	}
+	if sxx == 0 {
+		return 0, my, 0
+	}
+	slope = sxy / sxx
+	intercept = my - slope*mx
+	if n > 2 {
+		var sse float64
+		for i := range xs {
+			r := ys[i] - (intercept + slope*xs[i])
+			sse += r * r
+		}
+		stderr = math.Sqrt(sse/(n-2)) / math.Sqrt(sxx)
+	}
+	return slope, intercept, stderr
+}
+
+// runBenchHistoryMenu reports the trend of one machine's bench history
+// (this machine by default) and writes it to analysis/bench_trend.json.
+func runBenchHistoryMenu() {
+	reader := bufio.NewReader(os.Stdin)
+
+	dir, err := EnsurePublicDir("bench_history")
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if len(files) == 0 {
+		fmt.Printf("❌ No bench history in %s (run the microbench with --bench-history)\n", dir)
+		return
+	}
+	sort.Strings(files)
+
+	self := hashSystemInfo(Collect())
+	def := 0
+	fmt.Println("\nMachines with bench history:")
+	for i, f := range files {
+		id := strings.TrimSuffix(filepath.Base(f), ".jsonl")
+		mark := ""
+		if id == self {
+			def, mark = i, " (this machine)"
+		}
+		fmt.Printf("%d) %s%s\n", i+1, id, mark)
+	}
+	fmt.Printf("Select [default %d]: ", def+1)
+	raw, _ := reader.ReadString('\n')
+	idx := def
+	if s := strings.TrimSpace(raw); s != "" {
+		var n int
+		if _, err := fmt.Sscanf(s, "%d", &n); err != nil || n < 1 || n > len(files) {
+			fmt.Println("❌ Invalid selection")
+			return
+		}
+		idx = n - 1
+	}
+
+	entries, bad, err := loadBenchHistory(files[idx])
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	if bad > 0 {
+		fmt.Printf("⚠️  Skipped %d unreadable line(s)\n", bad)
+	}
+	id := strings.TrimSuffix(filepath.Base(files[idx]), ".jsonl")
+	report := BenchTrendReport{MachineID: id, GeneratedAt: time.Now().UTC(), Entries: len(entries), Trends: benchTrends(entries)}
+	if len(report.Trends) == 0 {
+		fmt.Printf("ℹ️  %d run(s) recorded; a trend needs at least 3 runs of a type.\n", len(entries))
+		return
+	}
+
+	fmt.Printf("\n📈 Bench trend for %s (%d runs, %s → %s)\n", id, len(entries),
+		entries[0].StartedAt.Format("2006-01-02"), entries[len(entries)-1].StartedAt.Format("2006-01-02"))
+	fmt.Println("-------------------------------------------------------------------------")
+	fmt.Printf("%-8s | %-6s | %4s | %12s | %14s | %8s\n", "Type", "Thread", "Runs", "Mean ops/s", "Slope /day", "Change")
+	fmt.Println("-------------------------------------------------------------------------")
+	var degrading int
+	for _, t := range report.Trends {
+		flagStr := ""
+		if t.Degrading {
+			flagStr = "  ⚠️  degrading"
+			degrading++
+		}
+		fmt.Printf("%-8s | %-6s | %4d | %12s | %14.4g | %+7.1f%%%s\n",
+			t.Type, t.Threads, t.Runs, humanize(int(t.MeanOps)), t.SlopePerDay, t.ChangePct, flagStr)
+	}
+	fmt.Println("-------------------------------------------------------------------------")
+	if degrading > 0 {
+		fmt.Printf("⚠️  %d trend(s) lost ≥%.0f%% with a significant slope — check cooling, throttling or background load.\n", degrading, benchTrendDrop*100)
+	} else {
+		fmt.Println("✅ No significant degradation")
+	}
+
+	outDir, err := EnsurePublicDir("analysis")
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	outPath := filepath.Join(outDir, "bench_trend.json")
+	if err := writeJSON(outPath, report); err != nil {
+		fmt.Printf("❌ Failed to write %s: %v\n", outPath, err)
+		return
+	}
+	fmt.Printf("💾 Written → %s\n", outPath)
+}
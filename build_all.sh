@@ -4,6 +4,12 @@ set -euo pipefail
 OUT_DIR="public/compiled"
 mkdir -p "$OUT_DIR"
 
+# Build stamp served at /version and recorded in telemetry reports
+VERSION="${VERSION:-$(git describe --tags --always --dirty 2>/dev/null || echo dev)}"
+COMMIT="$(git rev-parse --short HEAD 2>/dev/null || echo dev)"
+BUILD_TIME="$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+LDFLAGS="-X main.Version=$VERSION -X main.Commit=$COMMIT -X main.BuildTime=$BUILD_TIME"
+
 build() {
   local goos=$1
   local goarch=$2
@@ -14,7 +20,7 @@ build() {
   # Each build runs in its own subshell so env vars don't leak
   (
     GOOS=$goos GOARCH=$goarch CGO_ENABLED=1 \
-      go build -o "$OUT_DIR/$output" .
+      go build -ldflags "$LDFLAGS" -o "$OUT_DIR/$output" .
   )
 }
 
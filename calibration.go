@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openfluke/paragon/v3"
+)
+
+// --logits adds pre-softmax logits to compare output and makes evaluate dump
+// test-set logits for external temperature scaling.
+var flagLogits = flag.Bool("logits", false, "Include raw pre-softmax logits in compare output and dump test-set logits on evaluate")
+
+func logitsEnabled() bool {
+	return flag.Parsed() && *flagLogits
+}
+
+// extractLogits returns the output layer's pre-activation values (bias + Σ w·x)
+// for input. paragon only exposes post-softmax outputs, so this runs a CPU
+// forward up to the penultimate layer (ForwardUntilLayer) and recomputes the
+// output sums from the connections. It always uses the CPU path, even on a
+// GPU-initialized network.
+func extractLogits[T paragon.Numeric](nn *paragon.Network[T], input [][]float64) []float64 {
+	out := nn.OutputLayer
+	if out < 1 {
+		return nil
+	}
+	nn.ForwardUntilLayer(input, out-1)
+
+	layer := nn.Layers[out]
+	logits := make([]float64, 0, layer.Width*layer.Height)
+	for y := 0; y < layer.Height; y++ {
+		for x := 0; x < layer.Width; x++ {
+			neuron := layer.Neurons[y][x]
+			sum := float64(neuron.Bias)
+			for _, c := range neuron.Inputs {
+				src := nn.Layers[c.SourceLayer].Neurons[c.SourceY][c.SourceX]
+				sum += float64(src.Value) * float64(c.Weight)
+			}
+			logits = append(logits, sum)
+		}
+	}
+	return logits
+}
+
+// LogitSample is one row of a calibration dump.
+type LogitSample struct {
+	Label  int       `json:"label"`
+	Logits []float64 `json:"logits"`
+}
+
+// dumpCalibrationLogits writes the test-set logits for name to
+// public/calibration/<name>, ready for fitting a softmax temperature.
+func dumpCalibrationLogits[T paragon.Numeric](nn *paragon.Network[T], name string, inputs, targets [][][]float64) (string, error) {
+	rows := make([]LogitSample, len(inputs))
+	for i := range inputs {
+		rows[i] = LogitSample{
+			Label:  argmax64(targets[i][0]),
+			Logits: roundSlice(extractLogits(nn, inputs[i]), 6),
+		}
+	}
+
+	p, err := PublicPath("calibration", filepath.FromSlash(name))
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(p, ".json") {
+		p += ".json"
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return "", err
+	}
+	if err := writeJSON(p, rows); err != nil {
+		return "", fmt.Errorf("write logits: %w", err)
+	}
+	return p, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+)
+
+// Command is one top-level menu entry. The commands table is the single
+// source of truth: the interactive menu, runChoice and --describe all read it.
+type Command struct {
+	Code         string `json:"code"` // what you type at the menu or pass as the first arg
+	Title        string `json:"title"`
+	NeedsMNIST   bool   `json:"needs_mnist"`   // checked via ensureMNISTOrGuide before running
+	NeedsModels  bool   `json:"needs_models"`  // reads the models dir (option 4 creates it)
+	NeedsGPU     bool   `json:"needs_gpu"`     // meaningful only with WebGPU (falls back to CPU otherwise)
+	NeedsNetwork bool   `json:"needs_network"` // talks to or serves over the network
+
+	// Run performs the command. Interactive menus report their own problems;
+	// a non-nil error only sets the exit status of `iso-demo <code>`.
+	Run func() error `json:"-"`
+}
+
+var errUnknownCommand = errors.New("unknown command")
+
+// noErr adapts a menu function that reports its own errors to Command.Run.
+func noErr(f func()) func() error {
+	return func() error { f(); return nil }
+}
+
+var flagDescribe = flag.Bool("describe", false, "Print the menu commands as JSON (code, title, requirements) and exit")
+
+var commands = []Command{
+	{Code: "1", Title: "Show computer info (JSON)", Run: noErr(doShowInfo)},
+	{Code: "2", Title: "Run MNIST experiment (download/train/test via PILOT)", NeedsNetwork: true, Run: doRunExperiment},
+	{Code: "3", Title: "Export MNIST images to PNG (public/mnist_png/all)", NeedsMNIST: true, Run: doExportPNGs},
+	{Code: "4", Title: "Create the models for testing", Run: noErr(createModelZoo)},
+	{Code: "5", Title: "Benchmark models CPU on digit samples 1 item of each number (1 to 9)", NeedsMNIST: true, NeedsModels: true,
+		Run: noErr(func() { runDigitBenchMenu(false) })},
+	{Code: "6", Title: "Benchmark models GPU on digit samples 1 item of each number (1 to 9)", NeedsMNIST: true, NeedsModels: true, NeedsGPU: true,
+		Run: noErr(func() { runDigitBenchMenu(true) })},
+	{Code: "7", Title: "Compare CPU vs GPU (choose model)", NeedsMNIST: true, NeedsModels: true, NeedsGPU: true, Run: noErr(runCompareMenu)},
+	{Code: "8", Title: "Train model(s): N epochs or until target ADHD%", NeedsMNIST: true, NeedsModels: true, Run: noErr(runTrainMenu)},
+	{Code: "9", Title: "Evaluate a model on Train/Test set (ADHD metrics)", NeedsMNIST: true, NeedsModels: true, Run: noErr(runEvaluateMenu)},
+	{Code: "10", Title: "Run CPU numeric microbench (duration/filter/format)", Run: noErr(runBenchMenu)},
+	{Code: "11", Title: "Web server: start/stop/status", NeedsNetwork: true, Run: noErr(runWebMenu)},
+	{Code: "12", Title: "Telemetry: pull models from host → run → push report", NeedsGPU: true, NeedsNetwork: true, Run: noErr(runTelemetryMenu)},
+	{Code: "13", Title: "Benchmark WebGPU init time distribution (choose model)", NeedsModels: true, NeedsGPU: true, Run: noErr(runGPUInitMenu)},
+	{Code: "14", Title: "Export model size vs accuracy/speed (all models → JSON/CSV)", NeedsMNIST: true, NeedsModels: true, Run: noErr(exportSizeVsAccuracy)},
+	{Code: "15", Title: "Manage models: delete / reset / warm-start / migrate / purge telemetry downloads", NeedsModels: true, Run: noErr(runManageModelsMenu)},
+	{Code: "16", Title: "Benchmark all models: GPU init + CPU/GPU forward (→ JSON)", NeedsMNIST: true, NeedsModels: true, NeedsGPU: true, Run: noErr(exportDeviceBench)},
+	{Code: "17", Title: "Model archive: export zoo / import .zip or .tar.gz", Run: noErr(runArchiveMenu)},
+	{Code: "18", Title: "Predict digits from an image file or directory", NeedsModels: true, Run: noErr(runPredictMenu)},
+	{Code: reuploadCommandCode, Title: "Re-upload pending telemetry reports to a host", NeedsNetwork: true, Run: noErr(runReuploadMenu)},
+	{Code: "20", Title: "Benchmark model JSON load/parse time (all models → JSON)", NeedsModels: true, Run: noErr(exportLoadBench)},
+	{Code: "21", Title: "Convert telemetry report(s) to per-sample CSV", Run: noErr(runTelemetryCSVMenu)},
+	{Code: "22", Title: "Show MNIST dataset statistics (class counts, pixels, blank images → JSON)", NeedsMNIST: true, Run: noErr(exportDatasetStats)},
+	{Code: "23", Title: "Bench history: per-type throughput trend for a machine (→ JSON)", Run: noErr(runBenchHistoryMenu)},
+	{Code: "24", Title: "Doctor: check the public directory layout (mnist, models, compiled)", Run: runDoctorMenu},
+}
+
+// findCommand returns the command for a menu code.
+func findCommand(code string) (Command, bool) {
+	for _, c := range commands {
+		if c.Code == code {
+			return c, true
+		}
+	}
+	return Command{}, false
+}
+
+// printMenu prints the numbered menu from the commands table.
+func printMenu() {
+	for _, c := range commands {
+		fmt.Printf("%s) %s\n", c.Code, c.Title)
+	}
+}
+
+// describeCommands renders the commands table for --describe.
+func describeCommands() string {
+	bz, _ := json.MarshalIndent(commands, "", "  ")
+	return string(bz)
+}
+
+// commandCodes is "0–N" for the unknown-option hint.
+func commandCodes() string {
+	return "0–" + strconv.Itoa(len(commands))
+}
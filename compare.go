@@ -2,7 +2,11 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
+	"math"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -10,87 +14,169 @@ import (
 	"github.com/openfluke/paragon/v3"
 )
 
-func formatAll(out []float64) string {
-	parts := make([]string, len(out))
-	for i, v := range out {
-		parts[i] = fmt.Sprintf("%d:%.4f", i, v)
+// formatVecHeader/formatVecRow render output vectors as aligned columns so
+// CPU and GPU values for the same class line up vertically.
+func formatVecHeader(n int) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%-6s", "class"))
+	for i := 0; i < n; i++ {
+		b.WriteString(fmt.Sprintf(" %8d", i))
 	}
-	return "[" + strings.Join(parts, ", ") + "]"
+	return b.String()
 }
 
-func compareSingleModel(modelPath string) {
-	// Load MNIST once
-	images, labels, err := loadMNISTData(MustPublicPath("mnist"))
-	if err != nil {
-		fmt.Println("❌ Failed to load MNIST:", err)
-		return
+func formatVecRow(label string, out []float64) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%-6s", label))
+	for _, v := range out {
+		b.WriteString(fmt.Sprintf(" %8.4f", v))
 	}
+	return b.String()
+}
 
-	// First index for each digit 0..9
-	firstIdx := make(map[int]int)
-	for i, lbl := range labels {
-		for d, v := range lbl[0] {
-			if v == 1.0 {
-				if _, seen := firstIdx[d]; !seen {
-					firstIdx[d] = i
-				}
-				break
-			}
+// --drift-threshold: digits whose CPU/GPU max-abs drift exceeds this get a
+// per-class breakdown, so normal output stays compact.
+var flagDriftThreshold = flag.Float64("drift-threshold", 1e-4, "Print per-class CPU vs GPU values for digits whose max-abs drift exceeds this")
+
+func driftThreshold() float64 {
+	if flag.Parsed() {
+		return *flagDriftThreshold
+	}
+	return 1e-4
+}
+
+// printDriftBreakdown lists CPU vs GPU per class for one digit, marking the
+// classes that diverge by more than driftThreshold().
+func printDriftBreakdown(digit int, cpu, gpu []float64) {
+	thr := driftThreshold()
+	fmt.Printf("   🔬 Drift breakdown for digit %d (threshold %.1e):\n", digit, thr)
+	fmt.Printf("      %-6s %12s %12s %12s\n", "class", "CPU", "GPU", "|Δ|")
+	for i := 0; i < len(cpu) && i < len(gpu); i++ {
+		d := math.Abs(cpu[i] - gpu[i])
+		mark := ""
+		if d > thr {
+			mark = "  ⚠️"
 		}
+		fmt.Printf("      %-6d %12.8f %12.8f %12.8f%s\n", i, cpu[i], gpu[i], d, mark)
 	}
+}
 
-	fmt.Printf("\n📦 Model: %s\n", modelPath)
+// CompareResult is the structured outcome of a CPU vs GPU comparison for one
+// model. compareSingleModel prints from it; tooling can marshal it directly.
+type CompareResult struct {
+	Model        string         `json:"model"`
+	GPUInitOK    bool           `json:"gpu_init_ok"`
+	GPUInitMS    float64        `json:"gpu_init_ms"`
+	GPUInitError string         `json:"gpu_init_error,omitempty"`
+	GPUFallback  bool           `json:"gpu_fallback"` // GPU init failed: the "GPU" side below is really CPU
+	PerDigit     []DigitCompare `json:"per_digit"`
 
-	// Load once (type-aware), then rebuild fresh topology
-	loaded, err := paragon.LoadNamedNetworkFromJSONFile(modelPath)
+	// Roll-ups across PerDigit
+	MeanMAE    float64 `json:"mean_mae"`
+	MaxDrift   float64 `json:"max_drift"`
+	WorstDigit int     `json:"worst_digit"` // -1 when nothing was compared
+	AgreeCount int     `json:"agree_count"`
+}
+
+type DigitCompare struct {
+	Digit     int       `json:"digit"`
+	Idx       int       `json:"idx"`
+	CPUPred   int       `json:"cpu_pred"`
+	GPUPred   int       `json:"gpu_pred"`
+	CPUMS     float64   `json:"cpu_ms"`
+	GPUMS     float64   `json:"gpu_ms"`
+	MaxAbs    float64   `json:"max_abs"`
+	MAE       float64   `json:"mae"`
+	CPUTopK   string    `json:"cpu_topk"`
+	GPUTopK   string    `json:"gpu_topk"`
+	CPUOutput []float64 `json:"cpu_output"`
+	CPULogits []float64 `json:"cpu_logits,omitempty"` // pre-softmax, only with --logits
+	GPUOutput []float64 `json:"gpu_output"`
+}
+
+func (r CompareResult) ToJSON() string {
+	bz, _ := json.MarshalIndent(r, "", "  ")
+	return string(bz)
+}
+
+// compareSingleModel runs CPU vs GPU on the digit samples (digits, nil for
+// all of 0..9) and prints the result. By default it prints the top-3 classes
+// per device; fullOutput prints all softmax values for both devices as
+// aligned columns.
+func compareSingleModel(modelPath string, fullOutput bool, digits []int) (CompareResult, error) {
+	res, err := runCompareCPUvsGPU(modelPath, digits)
 	if err != nil {
-		fmt.Printf("❌ Load failed: %v\n", err)
-		return
+		fmt.Println("❌", err)
+		return res, err
 	}
-	tmp, ok := loaded.(*paragon.Network[float32])
-	if !ok {
-		fmt.Printf("⚠️ Skipping (not float32): %T\n", loaded)
-		return
+	printCompareResult(res, fullOutput)
+	return res, nil
+}
+
+// runCompareCPUvsGPU does the actual work for compareSingleModel without printing.
+func runCompareCPUvsGPU(modelPath string, digits []int) (CompareResult, error) {
+	res := CompareResult{Model: modelPath, WorstDigit: -1}
+
+	// Load MNIST once
+	images, labels, err := getMNIST()
+	if err != nil {
+		return res, fmt.Errorf("failed to load MNIST: %w", err)
 	}
 
-	// Derive shapes/acts
-	shapes := make([]struct{ Width, Height int }, len(tmp.Layers))
-	acts := make([]string, len(tmp.Layers))
-	trains := make([]bool, len(tmp.Layers))
-	for i, L := range tmp.Layers {
-		shapes[i] = struct{ Width, Height int }{L.Width, L.Height}
-		a := "linear"
-		if L.Height > 0 && L.Width > 0 && L.Neurons[0][0] != nil {
-			a = L.Neurons[0][0].Activation
-		}
-		acts[i], trains[i] = a, true
+	// One index for each digit 0..9 (first occurrence unless --sample-nth/--sample-idx)
+	firstIdx, err := sampleIndexPerDigit(labels)
+	if err != nil {
+		return res, fmt.Errorf("select samples: %w", err)
+	}
+	if digits == nil {
+		digits = allDigits()
+	}
+	firstIdx = selectDigits(firstIdx, digits)
+
+	// Load once (type-aware), then rebuild fresh topology
+	loaded, err := paragon.LoadNamedNetworkFromJSONFile(modelPath)
+	if err != nil {
+		return res, fmt.Errorf("load failed: %w", err)
 	}
-	state, _ := tmp.MarshalJSONModel()
 
 	// Build CPU once
-	nnCPU, _ := paragon.NewNetwork[float32](shapes, acts, trains)
-	_ = nnCPU.UnmarshalJSONModel(state)
+	nnCPU, err := rebuildFloat32(loaded)
+	if err != nil {
+		return res, fmt.Errorf("skipping: %w", err)
+	}
 	nnCPU.WebGPUNative = false
 
 	// Build GPU once
-	nnGPU, _ := paragon.NewNetwork[float32](shapes, acts, trains)
-	_ = nnGPU.UnmarshalJSONModel(state)
+	nnGPU, err := rebuildFloat32(loaded)
+	if err != nil {
+		return res, fmt.Errorf("skipping: %w", err)
+	}
 	nnGPU.WebGPUNative = true
 	startInit := time.Now()
-	if err := nnGPU.InitializeOptimizedGPU(); err != nil {
-		fmt.Printf("⚠️ GPU init failed: %v\n   Falling back to CPU-only compare.\n", err)
+	if cpuOnly() {
+		res.GPUInitError = "disabled by --cpu-only"
+		nnGPU.WebGPUNative = false
+	} else if gnn, release, err := initGPUNet(nnGPU); err != nil {
+		res.GPUInitError = err.Error()
+		res.GPUFallback = true
+		warnGPUFallback("compare", err)
 		nnGPU.WebGPUNative = false
 	} else {
-		fmt.Printf("✅ WebGPU initialized in %v\n", time.Since(startInit))
+		nnGPU = gnn
+		defer release()
+		res.GPUInitOK = true
 		// Warmup to pay JIT/pipeline cost once
-		if idx, ok := firstIdx[0]; ok {
+		if idx, ok := firstIdx[digits[0]]; ok {
 			nnGPU.Forward(images[idx])
 			_ = nnGPU.ExtractOutput()
 		}
 	}
+	res.GPUInitMS = float64(time.Since(startInit).Microseconds()) / 1000.0
+	recordTiming("init", time.Since(startInit))
 
-	// Run digits 0..9
-	for d := 0; d <= 9; d++ {
+	var sumMAE float64
+
+	for _, d := range digits {
 		idx, ok := firstIdx[d]
 		if !ok {
 			continue
@@ -101,34 +187,241 @@ func compareSingleModel(modelPath string) {
 		startCPU := time.Now()
 		nnCPU.Forward(sample)
 		outCPU := nnCPU.ExtractOutput()
-		elapsedCPU := time.Since(startCPU)
-		predCPU := argmax64(outCPU)
+		elapsedCPU := float64(time.Since(startCPU).Microseconds()) / 1000.0
 
 		// GPU (may be CPU fallback if init failed)
 		startGPU := time.Now()
 		nnGPU.Forward(sample)
 		outGPU := nnGPU.ExtractOutput()
-		elapsedGPU := time.Since(startGPU)
-		predGPU := argmax64(outGPU)
+		elapsedGPU := float64(time.Since(startGPU).Microseconds()) / 1000.0
 
 		maxAbs, mae := driftMaxAndMAE(outCPU, outGPU)
 
+		dc := DigitCompare{
+			Digit: d, Idx: idx,
+			CPUPred: argmax64(outCPU), GPUPred: argmax64(outGPU),
+			CPUMS: elapsedCPU, GPUMS: elapsedGPU,
+			MaxAbs: maxAbs, MAE: mae,
+			CPUTopK: formatTopK(outCPU, 3), GPUTopK: formatTopK(outGPU, 3),
+			CPUOutput: outCPU, GPUOutput: outGPU,
+		}
+		if logitsEnabled() {
+			dc.CPULogits = roundSlice(extractLogits(nnCPU, sample), 6)
+		}
+		res.PerDigit = append(res.PerDigit, dc)
+		emitEvent("compare_digit", struct {
+			Model string `json:"model"`
+			DigitCompare
+		}{res.Model, dc})
+
+		sumMAE += mae
+		if res.WorstDigit < 0 || maxAbs > res.MaxDrift {
+			res.MaxDrift, res.WorstDigit = maxAbs, d
+		}
+		if dc.CPUPred == dc.GPUPred {
+			res.AgreeCount++
+		}
+	}
+	res.MeanMAE = safeDiv(sumMAE, float64(len(res.PerDigit)))
+
+	return res, nil
+}
+
+func printCompareResult(res CompareResult, fullOutput bool) {
+	fmt.Printf("\n📦 Model: %s\n", res.Model)
+	if res.GPUInitOK {
+		fmt.Printf("✅ WebGPU initialized in %.3fms\n", res.GPUInitMS)
+	} else {
+		fmt.Printf("⚠️  GPU side ran on CPU (%s) — drift numbers are CPU vs CPU.\n", res.GPUInitError)
+	}
+
+	for _, dc := range res.PerDigit {
+		if fullOutput {
+			fmt.Printf(
+				"Digit %d (idx=%d)\n   CPU pred=%d ⏱ %.3fms | GPU pred=%d ⏱ %.3fms\n   %s\n   %s\n   %s\n   drift_max=%.6f mae=%.6f\n",
+				dc.Digit, dc.Idx,
+				dc.CPUPred, dc.CPUMS, dc.GPUPred, dc.GPUMS,
+				formatVecHeader(len(dc.CPUOutput)),
+				formatVecRow("CPU", dc.CPUOutput),
+				formatVecRow("GPU", dc.GPUOutput),
+				dc.MaxAbs, dc.MAE,
+			)
+		} else {
+			fmt.Printf(
+				"Digit %d (idx=%d)\n   CPU pred=%d %s ⏱ %.3fms\n   GPU pred=%d %s ⏱ %.3fms\n   drift_max=%.6f mae=%.6f\n",
+				dc.Digit, dc.Idx,
+				dc.CPUPred, dc.CPUTopK, dc.CPUMS,
+				dc.GPUPred, dc.GPUTopK, dc.GPUMS,
+				dc.MaxAbs, dc.MAE,
+			)
+		}
+		if len(dc.CPULogits) > 0 {
+			fmt.Printf("   %s\n", formatVecRow("logit", dc.CPULogits))
+		}
+		if dc.MaxAbs > driftThreshold() {
+			printDriftBreakdown(dc.Digit, dc.CPUOutput, dc.GPUOutput)
+		}
+	}
+
+	if len(res.PerDigit) > 0 {
+		fmt.Printf("📊 Summary over %d digits: mean_mae=%.6f max_drift=%.6f (worst digit=%d)\n",
+			len(res.PerDigit), res.MeanMAE, res.MaxDrift, res.WorstDigit)
+	}
+}
+
+// CompareMatrixRow is one model's line in the all-models CPU vs GPU matrix:
+// the full CompareResult plus the two roll-ups the matrix prints.
+type CompareMatrixRow struct {
+	CompareResult
+	AgreeRate  float64 `json:"agree_rate"`  // fraction of digits where CPU and GPU predict the same
+	GPUSpeedup float64 `json:"gpu_speedup"` // mean CPU ms / mean GPU ms (>1 means GPU is faster)
+}
+
+// compareAllModels runs runCompareCPUvsGPU on every model in modelDir, prints
+// a summary matrix and writes public/analysis/compare_all.json.
+func compareAllModels(modelDir string) {
+	models, err := listModels(modelDir)
+	if err != nil || len(models) == 0 {
+		fmt.Println("❌ No models found in public/models/")
+		return
+	}
+
+	var rows []CompareMatrixRow
+	for i, name := range models {
+		fmt.Printf("▶ [%d/%d] %s\n", i+1, len(models), name)
+		res, err := runCompareCPUvsGPU(filepath.Join(modelDir, name), nil)
+		if err != nil {
+			fmt.Printf("   ⚠️ %v\n", err)
+			continue
+		}
+		res.Model = name
+		row := CompareMatrixRow{CompareResult: res}
+		var cpuMS, gpuMS float64
+		for _, dc := range res.PerDigit {
+			cpuMS += dc.CPUMS
+			gpuMS += dc.GPUMS
+		}
+		row.AgreeRate = safeDiv(float64(res.AgreeCount), float64(len(res.PerDigit)))
+		row.GPUSpeedup = safeDiv(cpuMS, gpuMS)
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		fmt.Println("❌ No models compared")
+		return
+	}
+
+	fmt.Printf("\n%-24s | %-8s | %-10s | %-10s | %-7s | %-8s\n", "Model", "GPU", "Mean MAE", "Max drift", "Agree", "Speedup")
+	fmt.Println("-----------------------------------------------------------------------------------")
+	for _, r := range rows {
+		gpu := "ok"
+		if !r.GPUInitOK {
+			gpu = "fallback"
+		}
+		fmt.Printf("%-24s | %-8s | %-10.2e | %-10.2e | %-7s | %-8s\n", r.Model, gpu, r.MeanMAE, r.MaxDrift,
+			fmt.Sprintf("%.0f%%", r.AgreeRate*100), fmt.Sprintf("%.2fx", r.GPUSpeedup))
+	}
+
+	outDir, err := EnsurePublicDir("analysis")
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	jsonPath := filepath.Join(outDir, "compare_all.json")
+	if err := writeJSON(jsonPath, rows); err != nil {
+		fmt.Printf("❌ Failed to write %s: %v\n", jsonPath, err)
+		return
+	}
+	fmt.Printf("💾 Written → %s\n", jsonPath)
+}
+
+// compareTwoModels runs model A and model B on CPU over the fixed 0..9 digit
+// samples and reports prediction agreement, output drift, and where they diverge.
+// Handy for "did my retrain actually change predictions?".
+func compareTwoModels(pathA, pathB string) {
+	images, labels, err := getMNIST()
+	if err != nil {
+		fmt.Println("❌ Failed to load MNIST:", err)
+		return
+	}
+	firstIdx, err := sampleIndexPerDigit(labels)
+	if err != nil {
+		fmt.Println("❌ Sample selection:", err)
+		return
+	}
+
+	nnA, err := loadFloat32Model(pathA)
+	if err != nil {
+		fmt.Printf("❌ Model A (%s): %v\n", filepath.Base(pathA), err)
+		return
+	}
+	nnB, err := loadFloat32Model(pathB)
+	if err != nil {
+		fmt.Printf("❌ Model B (%s): %v\n", filepath.Base(pathB), err)
+		return
+	}
+
+	fmt.Printf("\n📦 A: %s\n📦 B: %s\n", pathA, pathB)
+
+	var sumMAE, worstMaxAbs float64
+	var diverged []int
+	agree, compared := 0, 0
+
+	for d := 0; d <= 9; d++ {
+		idx, ok := firstIdx[d]
+		if !ok {
+			continue
+		}
+		sample := images[idx]
+
+		nnA.Forward(sample)
+		outA := nnA.ExtractOutput()
+		nnB.Forward(sample)
+		outB := nnB.ExtractOutput()
+
+		predA, predB := argmax64(outA), argmax64(outB)
+		maxAbs, mae := driftMaxAndMAE(outA, outB)
+
+		mark := "="
+		if predA == predB {
+			agree++
+		} else {
+			mark = "≠"
+			diverged = append(diverged, d)
+		}
 		fmt.Printf(
-			"Digit %d (idx=%d)\n   CPU pred=%d %s ⏱ %v\n   GPU pred=%d %s ⏱ %v\n   drift_max=%.6f mae=%.6f\n",
-			d, idx,
-			predCPU, formatAll(outCPU), elapsedCPU,
-			predGPU, formatAll(outGPU), elapsedGPU,
+			"Digit %d (idx=%d) %s\n   A pred=%d %s\n   B pred=%d %s\n   drift_max=%.6f mae=%.6f\n",
+			d, idx, mark,
+			predA, formatTopK(outA, 3),
+			predB, formatTopK(outB, 3),
 			maxAbs, mae,
 		)
+
+		sumMAE += mae
+		if maxAbs > worstMaxAbs {
+			worstMaxAbs = maxAbs
+		}
+		compared++
 	}
 
-	if nnGPU.WebGPUNative {
-		nnGPU.CleanupOptimizedGPU()
+	if compared == 0 {
+		fmt.Println("⚠️ No digit samples available")
+		return
+	}
+	fmt.Printf("📊 Agreement: %d/%d | mean_mae=%.6f max_drift=%.6f\n",
+		agree, compared, sumMAE/float64(compared), worstMaxAbs)
+	if len(diverged) > 0 {
+		fmt.Printf("🔀 Predictions diverge on digits: %v\n", diverged)
+	} else {
+		fmt.Println("✅ Identical predictions on all compared digits")
 	}
 }
 
+// driftMaxAndMAE compares two outputs element-wise. Outputs that are empty
+// or of different lengths can't be compared: it warns and returns 0, 0, so
+// the run carries on (an empty side also shows up as pred=-1).
 func driftMaxAndMAE(a, b []float64) (maxAbs float64, mae float64) {
 	if len(a) == 0 || len(a) != len(b) {
+		fmt.Printf("⚠️  Cannot compare outputs of length %d and %d; drift set to 0\n", len(a), len(b))
 		return 0, 0
 	}
 	sum := 0.0
@@ -146,7 +439,12 @@ func driftMaxAndMAE(a, b []float64) (maxAbs float64, mae float64) {
 	return
 }
 
+// formatTopK renders the k highest classes as "[i:v, ...]"; an empty output
+// renders as "[empty output]".
 func formatTopK(p []float64, k int) string {
+	if len(p) == 0 {
+		return "[empty output]"
+	}
 	type pair struct {
 		i int
 		v float64
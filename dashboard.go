@@ -0,0 +1,115 @@
+package main
+
+import (
+	_ "embed"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// The dashboard ships inside the binary so a host works without any extra
+// files in public/.
+//
+//go:embed assets/dashboard.html
+var dashboardHTML []byte
+
+// Counters behind /metrics; bumped by middleware and the upload handler.
+var webMetrics struct {
+	requests   atomic.Int64
+	uploads    atomic.Int64
+	duplicates atomic.Int64 // uploads skipped because identical content was already stored
+}
+
+// WebMetrics is the JSON shape served at /metrics.
+type WebMetrics struct {
+	UptimeSec  float64 `json:"uptime_sec"`
+	Requests   int64   `json:"requests"`
+	Uploads    int64   `json:"uploads"`
+	Duplicates int64   `json:"duplicate_uploads"`
+	Reports    int     `json:"reports"`
+	Goroutines int     `json:"goroutines"`
+	HeapBytes  uint64  `json:"heap_bytes"`
+}
+
+// ReportEntry is one row of /reports/index.json.
+type ReportEntry struct {
+	Name    string    `json:"name"`
+	Bytes   int64     `json:"bytes"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// RegisterDashboard mounts /dashboard plus the JSON endpoints it reads
+// (/sysinfo, /metrics). /reports/index.json lives with the upload routes.
+func RegisterDashboard(app *fiber.App, baseDir string) {
+	reportsDir := filepath.Join(baseDir, "reports")
+
+	app.Get("/dashboard", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.Send(dashboardHTML)
+	})
+
+	app.Get("/sysinfo", func(c *fiber.Ctx) error {
+		return c.JSON(Collect())
+	})
+
+	app.Get("/metrics", func(c *fiber.Ctx) error {
+		var ms runtime.MemStats
+		runtime.ReadMemStats(&ms)
+		reports, _ := listReports(reportsDir)
+		return c.JSON(WebMetrics{
+			UptimeSec:  time.Since(ws.startedAt).Seconds(),
+			Requests:   webMetrics.requests.Load(),
+			Uploads:    webMetrics.uploads.Load(),
+			Duplicates: webMetrics.duplicates.Load(),
+			Reports:    len(reports),
+			Goroutines: runtime.NumGoroutine(),
+			HeapBytes:  ms.HeapAlloc,
+		})
+	})
+}
+
+// listReports returns the .json files under dir, newest first. It walks
+// subdirectories too (REPORTS_PARTITION=date stores uploads under
+// YYYY-MM-DD/), so Name is the slash-separated path relative to dir.
+func listReports(dir string) ([]ReportEntry, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	out := []ReportEntry{}
+	err := filepath.WalkDir(dir, func(p string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // unreadable entry: skip it, keep listing the rest
+		}
+		if strings.HasPrefix(e.Name(), ".") && p != dir {
+			if e.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") || e.Name() == "index.json" {
+			return nil
+		}
+		fi, err := e.Info()
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return nil
+		}
+		out = append(out, ReportEntry{Name: filepath.ToSlash(rel), Bytes: fi.Size(), ModTime: fi.ModTime().UTC()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ModTime.After(out[j].ModTime) })
+	return out, nil
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Doctor statuses, worst last.
+const (
+	doctorOK      = "ok"
+	doctorInfo    = "info"
+	doctorWarn    = "warn"
+	doctorMissing = "missing"
+)
+
+// DoctorCheck is one finding about the public tree.
+type DoctorCheck struct {
+	Item   string `json:"item"`
+	Status string `json:"status"` // ok | info | warn | missing
+	Detail string `json:"detail"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+// publicLayout is every top-level entry the tool reads or writes under the
+// public dir. Anything else there is reported as stray.
+var publicLayout = map[string]bool{
+	"index.html": true, "mnist": true, "mnist_png": true, "models": true,
+	"models_remote": true, "reports": true, "reports_local": true,
+	"compiled": true, "analysis": true, "evals": true, "batch": true,
+	"exports": true, "calibration": true, "golden": true, "bench_history": true,
+}
+
+// idxMagic is the IDX header magic of each MNIST file.
+var idxMagic = map[string]uint32{
+	"train-images-idx3-ubyte": 2051, "t10k-images-idx3-ubyte": 2051,
+	"train-labels-idx1-ubyte": 2049, "t10k-labels-idx1-ubyte": 2049,
+}
+
+// runDoctor checks the public tree and returns one finding per item.
+func runDoctor() []DoctorCheck {
+	var out []DoctorCheck
+	add := func(item, status, detail, fix string) {
+		out = append(out, DoctorCheck{Item: item, Status: status, Detail: detail, Fix: fix})
+	}
+
+	base, err := BaseDir()
+	if err != nil {
+		add("public dir", doctorMissing, err.Error(), "fix --base / PARAGON_DATA_DIR, or run from a writable directory")
+		return out
+	}
+	if f, err := os.CreateTemp(base, ".doctor-*"); err != nil {
+		add("public dir", doctorWarn, base+" is not writable: "+err.Error(), "run from a writable directory or pass --base")
+	} else {
+		f.Close()
+		os.Remove(f.Name())
+		add("public dir", doctorOK, base, "")
+	}
+
+	out = append(out, doctorMNIST(base)...)
+	out = append(out, doctorModels(ModelsDir())...)
+
+	compiled := filepath.Join(base, "compiled")
+	if entries, err := os.ReadDir(compiled); err != nil || len(entries) == 0 {
+		add("compiled", doctorInfo, "no client binaries in "+compiled+" (only needed when hosting telemetry)",
+			"run ./build_all.sh — it writes to public/compiled")
+	} else {
+		add("compiled", doctorOK, fmt.Sprintf("%d file(s)", len(entries)), "")
+	}
+
+	if pending, err := pendingReports(filepath.Join(base, "reports_local")); err == nil && len(pending) > 0 {
+		add("reports_local", doctorWarn, fmt.Sprintf("%d report(s) never uploaded", len(pending)),
+			"option "+reuploadCommandCode+" (re-upload pending telemetry reports)")
+	}
+
+	if entries, err := os.ReadDir(base); err == nil {
+		var stray []string
+		for _, e := range entries {
+			if !publicLayout[e.Name()] && !strings.HasPrefix(e.Name(), ".") {
+				stray = append(stray, e.Name())
+			}
+		}
+		if len(stray) > 0 {
+			add("stray files", doctorInfo, strings.Join(stray, ", "), "not used by the tool; move them out if they were meant to go somewhere else")
+		}
+	}
+	return out
+}
+
+// doctorMNIST checks the four IDX files: present, readable headers with the
+// right magic, and images/labels counts that agree per set. It also spots
+// files left gzipped or dropped in the wrong directory.
+func doctorMNIST(base string) []DoctorCheck {
+	dir := filepath.Join(base, "mnist")
+	fix := "option 2 (downloads MNIST), option 12 (pulls it from a host), or copy the IDX files into " + dir
+	var out []DoctorCheck
+
+	counts := map[string]uint32{}
+	var missing []string
+	for _, fn := range mnistFiles {
+		p := filepath.Join(dir, fn)
+		n, err := idxCount(p, idxMagic[fn])
+		switch {
+		case os.IsNotExist(err):
+			missing = append(missing, fn)
+			var where []string
+			for _, alt := range []string{p + ".gz", filepath.Join(base, fn), filepath.Join(base, fn+".gz")} {
+				if _, err := os.Stat(alt); err == nil {
+					where = append(where, alt)
+				}
+			}
+			if len(where) > 0 {
+				out = append(out, DoctorCheck{Item: "mnist/" + fn, Status: doctorWarn,
+					Detail: "misplaced: found " + strings.Join(where, ", "),
+					Fix:    "move it to " + p + " (gunzip .gz files first)"})
+			}
+		case err != nil:
+			out = append(out, DoctorCheck{Item: "mnist/" + fn, Status: doctorWarn, Detail: err.Error(), Fix: "replace the file; " + fix})
+		default:
+			counts[fn] = n
+		}
+	}
+	if len(missing) > 0 {
+		out = append([]DoctorCheck{{Item: "mnist", Status: doctorMissing, Detail: "missing " + strings.Join(missing, ", "), Fix: fix}}, out...)
+		return out
+	}
+	for _, set := range []string{"train", "t10k"} {
+		imgs, lbls := counts[set+"-images-idx3-ubyte"], counts[set+"-labels-idx1-ubyte"]
+		if imgs != lbls {
+			out = append(out, DoctorCheck{Item: "mnist/" + set, Status: doctorWarn,
+				Detail: fmt.Sprintf("%d images but %d labels — a truncated or mismatched download", imgs, lbls), Fix: fix})
+		}
+	}
+	if len(out) == 0 {
+		out = append(out, DoctorCheck{Item: "mnist", Status: doctorOK,
+			Detail: fmt.Sprintf("%d train + %d t10k samples", counts["train-images-idx3-ubyte"], counts["t10k-images-idx3-ubyte"])})
+	}
+	return out
+}
+
+// idxCount reads an IDX header and returns its item count after checking
+// the magic number.
+func idxCount(path string, magic uint32) (uint32, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	var hdr [8]byte
+	if _, err := io.ReadFull(f, hdr[:]); err != nil {
+		return 0, fmt.Errorf("unreadable header: %w", err)
+	}
+	if m := binary.BigEndian.Uint32(hdr[:4]); m != magic {
+		return 0, fmt.Errorf("bad IDX magic %d (want %d) — gzipped or not an MNIST file?", m, magic)
+	}
+	return binary.BigEndian.Uint32(hdr[4:]), nil
+}
+
+// doctorModels checks the models dir against its manifest.
+func doctorModels(dir string) []DoctorCheck {
+	fix := "option 4 (create the model zoo)"
+	models, err := listModels(dir)
+	if err != nil || len(models) == 0 {
+		return []DoctorCheck{{Item: "models", Status: doctorMissing, Detail: "no models in " + dir, Fix: fix}}
+	}
+	out := []DoctorCheck{{Item: "models", Status: doctorOK, Detail: fmt.Sprintf("%d model(s) in %s", len(models), dir)}}
+
+	specs, err := readManifest(filepath.Join(dir, "manifest.json"))
+	switch {
+	case err != nil:
+		out = append(out, DoctorCheck{Item: "models/manifest.json", Status: doctorWarn, Detail: err.Error(), Fix: fix + " rewrites it"})
+		return out
+	case specs == nil:
+		out = append(out, DoctorCheck{Item: "models/manifest.json", Status: doctorWarn,
+			Detail: "no manifest: reset and describe fall back to loading each model", Fix: fix + " writes it (existing models are kept)"})
+		return out
+	}
+	onDisk := map[string]bool{}
+	for _, m := range models {
+		onDisk[m] = true
+	}
+	var gone, extra []string
+	listed := map[string]bool{}
+	for _, s := range specs {
+		listed[s.Filename] = true
+		if !onDisk[s.Filename] {
+			gone = append(gone, s.Filename)
+		}
+	}
+	for _, m := range models {
+		if !listed[m] {
+			extra = append(extra, m)
+		}
+	}
+	sort.Strings(gone)
+	if len(gone) > 0 {
+		out = append(out, DoctorCheck{Item: "models/manifest.json", Status: doctorWarn,
+			Detail: "listed but missing: " + strings.Join(gone, ", "), Fix: fix + " rebuilds missing models"})
+	} else {
+		out = append(out, DoctorCheck{Item: "models/manifest.json", Status: doctorOK, Detail: fmt.Sprintf("%d entries", len(specs))})
+	}
+	if len(extra) > 0 {
+		out = append(out, DoctorCheck{Item: "models", Status: doctorInfo,
+			Detail: "not in the manifest (trained copies, imports): " + strings.Join(extra, ", ")})
+	}
+	return out
+}
+
+// doctorIcon is the marker printed for each status.
+func doctorIcon(status string) string {
+	switch status {
+	case doctorOK:
+		return "✅"
+	case doctorInfo:
+		return "ℹ️ "
+	case doctorWarn:
+		return "⚠️ "
+	default:
+		return "❌"
+	}
+}
+
+// runDoctorMenu prints runDoctor's findings with a fix for each gap. It
+// returns an error when something required is missing, so `iso-demo <code>`
+// can gate scripts on it.
+func runDoctorMenu() error {
+	checks := runDoctor()
+	fmt.Println("\n🩺 Public directory check:")
+	var missing, warn int
+	for _, c := range checks {
+		fmt.Printf("%s %-22s %s\n", doctorIcon(c.Status), c.Item, c.Detail)
+		if c.Fix != "" && c.Status != doctorOK {
+			fmt.Printf("   ↳ fix: %s\n", c.Fix)
+		}
+		switch c.Status {
+		case doctorMissing:
+			missing++
+		case doctorWarn:
+			warn++
+		}
+	}
+	switch {
+	case missing > 0:
+		fmt.Printf("❌ %d missing, %d warning(s)\n", missing, warn)
+		return fmt.Errorf("doctor: %d required item(s) missing", missing)
+	case warn > 0:
+		fmt.Printf("⚠️  %d warning(s)\n", warn)
+	default:
+		fmt.Println("✅ Everything in place")
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIdxCountTruncatedHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "train-labels-idx1-ubyte")
+	// the magic number alone, without the count
+	if err := os.WriteFile(path, []byte{0, 0, 8, 1}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := idxCount(path, 2049); err == nil {
+		t.Fatalf("truncated header read as count %d", n)
+	}
+
+	if err := os.WriteFile(path, []byte{0, 0, 8, 1, 0, 0, 0, 3, 1, 2, 3}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := idxCount(path, 2049); err != nil || n != 3 {
+		t.Fatalf("idxCount = %d, %v; want 3", n, err)
+	}
+}
@@ -0,0 +1,15 @@
+package main
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by the model, GPU, telemetry and MNIST
+// helpers so callers — including the HTTP handlers — can branch with errors.Is
+// instead of matching message strings.
+var (
+	ErrModelNotFloat32 = errors.New("model is not float32")
+	ErrGPUInitFailed   = errors.New("webgpu init failed")
+	ErrManifestEmpty   = errors.New("model manifest is empty")
+	ErrMNISTMissing    = errors.New("mnist files missing")
+	ErrUploadFailed    = errors.New("report upload failed")
+	ErrInvalidHost     = errors.New("invalid host base")
+)
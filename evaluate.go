@@ -2,151 +2,584 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
+	"math"
+	"math/rand"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/openfluke/paragon/v3"
 )
 
+// EvalResult is what an evaluation run persists to public/evals/<model>.json,
+// so batch evaluation can tell which models are already up to date.
+type EvalResult struct {
+	Model       string    `json:"model"`
+	TrainScore  float64   `json:"train_score"`
+	TestScore   float64   `json:"test_score"`
+	GPU         bool      `json:"gpu"`
+	GPUFallback bool      `json:"gpu_fallback"`          // GPU was requested but init failed, so the run was CPU
+	Split       string    `json:"split"`                 // "official" (60k/10k) or "seeded_80_20"
+	LossMetric  string    `json:"loss_metric,omitempty"` // --loss: "xent" | "mse"
+	TrainLoss   float64   `json:"train_loss,omitempty"`
+	TestLoss    float64   `json:"test_loss,omitempty"`
+	EvaluatedAt time.Time `json:"evaluated_at"`
+
+	runCtx *RunContext // captured when the evaluation started; saved beside the result
+}
+
+// --force re-runs batch work (e.g. batch evaluation) even when up-to-date results exist.
+var flagForce = flag.Bool("force", false, "Re-run batch operations even if up-to-date results exist")
+
+func forceRerun() bool {
+	return flag.Parsed() && *flagForce
+}
+
 func runEvaluateMenu() {
-	modelDir := MustPublicPath("models")
+	modelDir := ModelsDir()
 
-	entries, _ := os.ReadDir(modelDir)
-	models := []string{}
-	for _, e := range entries {
-		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") || e.Name() == "manifest.json" {
-			continue
-		}
-		models = append(models, e.Name())
-	}
+	models, _ := listModels(modelDir)
 	if len(models) == 0 {
 		fmt.Println("❌ No models found in public/models/")
 		return
 	}
 
-	fmt.Println("\nAvailable models:")
-	for i, m := range models {
-		fmt.Printf("%d) %s\n", i+1, m)
-	}
-	fmt.Println("0) Back")
-
 	reader := bufio.NewReader(os.Stdin)
-	fmt.Print("Select model: ")
-	choiceRaw, _ := reader.ReadString('\n')
-	choice := strings.TrimSpace(choiceRaw)
-	if choice == "0" {
+
+	fmt.Println("\nEvaluate what?")
+	fmt.Println("1) Single model")
+	fmt.Println("2) All models (skips models with an up-to-date result unless --force)")
+	fmt.Println("3) Quick score (random test sample, approximate)")
+	fmt.Println("0) Back")
+	fmt.Print("Select: ")
+	modeRaw, _ := reader.ReadString('\n')
+	mode := strings.TrimSpace(modeRaw)
+	if mode == "0" {
 		return
 	}
-	idx, err := strconv.Atoi(choice)
-	if err != nil || idx < 1 || idx > len(models) {
+	if mode != "1" && mode != "2" && mode != "3" {
 		fmt.Println("❌ Invalid choice")
 		return
 	}
 
-	modelPath := filepath.Join(modelDir, models[idx-1])
-	fmt.Printf("\n▶ Evaluating %s\n", models[idx-1])
-	evaluateModelADHD(modelPath)
+	var chosen []string
+	if mode == "1" || mode == "3" {
+		status := loadModelStatus()
+		fmt.Println("\nAvailable models:")
+		for i, m := range models {
+			fmt.Printf("%d) %s%s %s\n", i+1, m, modelChoiceLabel(modelDir, m), modelStatusLabel(status, m))
+		}
+		fmt.Println("0) Back")
+
+		fmt.Print("Select model: ")
+		choiceRaw, _ := reader.ReadString('\n')
+		choice := strings.TrimSpace(choiceRaw)
+		if choice == "0" {
+			return
+		}
+		idx, err := strconv.Atoi(choice)
+		if err != nil || idx < 1 || idx > len(models) {
+			fmt.Println("❌ Invalid choice")
+			return
+		}
+		chosen = []string{models[idx-1]}
+	} else {
+		chosen = models
+	}
+
+	if mode == "3" {
+		runQuickScorePrompt(reader, chosen[0])
+		return
+	}
+
+	skipGPU := cpuOnly()
+	fmt.Printf("CPU only (skip WebGPU init)? [y/N] (default %s): ", ternary(skipGPU, "y", "n"))
+	if s, _ := reader.ReadString('\n'); strings.TrimSpace(s) != "" {
+		skipGPU = strings.EqualFold(strings.TrimSpace(s), "y")
+	}
+
+	skipped := 0
+	for i, name := range chosen {
+		modelPath := filepath.Join(modelDir, name)
+		if mode == "2" && !forceRerun() && evalUpToDate(name, modelPath) {
+			fmt.Printf("⏭  [%d/%d] %s: eval result is newer than the model, skipping\n", i+1, len(chosen), name)
+			skipped++
+			continue
+		}
+
+		fmt.Printf("\n▶ [%d/%d] Evaluating %s\n", i+1, len(chosen), name)
+		res, err := evaluateModelADHD(modelPath, skipGPU)
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", name, err)
+			continue
+		}
+		res.Model = name
+		if err := saveEvalResult(name, res); err != nil {
+			fmt.Printf("⚠️  Could not save eval result for %s: %v\n", name, err)
+		}
+	}
+	if skipped > 0 {
+		fmt.Printf("ℹ️  Skipped %d up-to-date model(s); pass --force to re-evaluate them.\n", skipped)
+	}
 }
 
-func evaluateModelADHD(modelPath string) {
-	// Load dataset
-	images, labels, err := loadMNISTData(MustPublicPath("mnist"))
+// defaultQuickSamples is the quick-score sample size when none is given.
+const defaultQuickSamples = 1000
+
+func runQuickScorePrompt(reader *bufio.Reader, name string) {
+	n := defaultQuickSamples
+	fmt.Printf("Sample size [default %d]: ", n)
+	if raw, _ := reader.ReadString('\n'); strings.TrimSpace(raw) != "" {
+		v, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || v < 1 {
+			fmt.Println("❌ Invalid sample size")
+			return
+		}
+		n = v
+	}
+	seed := int64(42)
+	fmt.Printf("Seed [default %d]: ", seed)
+	if raw, _ := reader.ReadString('\n'); strings.TrimSpace(raw) != "" {
+		if v, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64); err == nil {
+			seed = v
+		}
+	}
+
+	fmt.Printf("\n▶ Quick-scoring %s on %d sampled test examples (seed %d)\n", name, n, seed)
+	res, err := sampledScore(filepath.Join(ModelsDir(), name), n, seed)
 	if err != nil {
-		fmt.Println("❌ Failed to load MNIST:", err)
+		fmt.Printf("❌ %s: %v\n", name, err)
 		return
 	}
-	trainInputs, trainTargets, testInputs, testTargets := paragon.SplitDataset(images, labels, 0.8)
+	res.Model = name
+	fmt.Printf("\n≈ Quick Score: %.4f%% (sample estimate, not the full test set)\n", res.Score)
+	fmt.Printf("≈ Accuracy: %.2f%% (95%% CI %.2f%%–%.2f%%, n=%d)\n",
+		res.Accuracy*100, res.AccuracyCI95[0]*100, res.AccuracyCI95[1]*100, res.Samples)
+
+	if p, err := PublicPath("evals", "quick", filepath.FromSlash(name)); err != nil {
+		fmt.Printf("⚠️  Could not save quick score: %v\n", err)
+	} else if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		fmt.Printf("⚠️  Could not save quick score: %v\n", err)
+	} else if err := writeJSON(p, res); err != nil {
+		fmt.Printf("⚠️  Could not save quick score: %v\n", err)
+	} else {
+		fmt.Printf("💾 Quick score → %s\n", p)
+	}
+}
+
+// SampledScore is a score computed over a random sample of the test pool
+// rather than the whole set, with a confidence interval on the accuracy so
+// two sampled results can be compared honestly.
+type SampledScore struct {
+	Model        string     `json:"model"`
+	Samples      int        `json:"samples"`
+	Pool         int        `json:"pool"` // size of the test pool the sample was drawn from
+	Seed         int64      `json:"seed"`
+	Split        string     `json:"split"`         // splitLabel(): "official" (t10k) or "seeded_80_20"
+	Score        float64    `json:"score"`         // ADHD score over the sample
+	Accuracy     float64    `json:"accuracy"`      // fraction of the sample classified correctly
+	AccuracyCI95 [2]float64 `json:"accuracy_ci95"` // normal-approximation 95% interval on Accuracy
+	EvaluatedAt  time.Time  `json:"evaluated_at"`
+}
+
+// quickScore estimates a model's ADHD test score from n test examples drawn
+// by reservoir sampling. See sampledScore for how the sample is chosen.
+func quickScore(modelPath string, n int, seed int64) (float64, error) {
+	res, err := sampledScore(modelPath, n, seed)
+	return res.Score, err
+}
+
+// sampledScore scores a model on n reservoir-sampled test examples drawn
+// from the test side of splitMNIST — the same samples training held out, so
+// the estimate isn't train-contaminated, and the same n and seed pick the
+// same examples for every model. It always runs on CPU.
+func sampledScore(modelPath string, n int, seed int64) (SampledScore, error) {
+	res := SampledScore{Model: filepath.Base(modelPath), Seed: seed}
+	if n < 1 {
+		return res, fmt.Errorf("sample size must be positive, got %d", n)
+	}
+	images, labels, err := getMNIST()
+	if err != nil {
+		return res, fmt.Errorf("failed to load MNIST: %w", err)
+	}
+	_, _, poolIn, poolTarg := splitMNIST(images, labels)
+	res.Split = splitLabel()
+	pool := len(poolIn)
+	if pool == 0 {
+		return res, fmt.Errorf("no test examples available")
+	}
 
-	// Load saved network
 	loaded, err := paragon.LoadNamedNetworkFromJSONFile(modelPath)
 	if err != nil {
-		fmt.Printf("❌ Load failed: %v\n", err)
-		return
+		return res, fmt.Errorf("load failed: %w", err)
 	}
-	tmp, ok := loaded.(*paragon.Network[float32])
-	if !ok {
-		fmt.Printf("⚠️ Skipping (not float32): %T\n", loaded)
-		return
+	nn, err := rebuildFloat32(loaded)
+	if err != nil {
+		return res, fmt.Errorf("skipping: %w", err)
 	}
+	nn.WebGPUNative = false
 
-	// Rebuild fresh network with correct shapes/acts
-	shapes := make([]struct{ Width, Height int }, len(tmp.Layers))
-	acts := make([]string, len(tmp.Layers))
-	trains := make([]bool, len(tmp.Layers))
-	for i, L := range tmp.Layers {
-		shapes[i] = struct{ Width, Height int }{L.Width, L.Height}
-		act := "linear"
-		if L.Height > 0 && L.Width > 0 && L.Neurons[0][0] != nil {
-			act = L.Neurons[0][0].Activation
-		}
-		acts[i], trains[i] = act, true
-	}
-	nn, err := paragon.NewNetwork[float32](shapes, acts, trains)
+	idx := reservoirSample(pool, n, seed)
+	inputs := make([][][]float64, len(idx))
+	targets := make([][][]float64, len(idx))
+	for i, j := range idx {
+		inputs[i], targets[i] = poolIn[j], poolTarg[j]
+	}
+
+	o := scoreNetwork(nn, inputs, targets)
+	recordTiming("eval", o.Elapsed)
+	fmt.Printf("ℹ️  Sampled %d of %d test examples; the score below is an estimate.\n", len(idx), pool)
+	res.Samples, res.Pool = len(idx), pool
+	res.Score = printEvalOutcome(o, inputs, "Quick Sample")
+	res.Accuracy = 1 - float64(len(o.Offenders))/float64(len(idx))
+	res.AccuracyCI95[0], res.AccuracyCI95[1] = proportionCI95(res.Accuracy, len(idx))
+	res.EvaluatedAt = time.Now().UTC()
+	return res, nil
+}
+
+// proportionCI95 is the normal-approximation (Wald) 95% confidence interval
+// for a proportion p observed over n trials, clamped to [0, 1].
+func proportionCI95(p float64, n int) (lo, hi float64) {
+	if n <= 0 {
+		return 0, 1
+	}
+	half := 1.96 * math.Sqrt(p*(1-p)/float64(n))
+	return math.Max(0, p-half), math.Min(1, p+half)
+}
+
+// reservoirSample picks min(k, n) distinct indices from [0, n) uniformly at
+// random (Algorithm R). The same seed always yields the same indices.
+func reservoirSample(n, k int, seed int64) []int {
+	if k > n {
+		k = n
+	}
+	rng := rand.New(rand.NewSource(seed))
+	res := make([]int, k)
+	for i := 0; i < n; i++ {
+		if i < k {
+			res[i] = i
+		} else if j := rng.Intn(i + 1); j < k {
+			res[j] = i
+		}
+	}
+	return res
+}
+
+// evalResultPath maps a model's relative name to public/evals/<name>.
+func evalResultPath(name string) (string, error) {
+	return PublicPath("evals", filepath.FromSlash(name))
+}
+
+// evalUpToDate reports whether a saved eval result exists and is newer than
+// the model file (same skip-if-exists idea as createModelZoo, plus mtime).
+func evalUpToDate(name, modelPath string) bool {
+	p, err := evalResultPath(name)
 	if err != nil {
-		fmt.Printf("❌ NewNetwork failed: %v\n", err)
-		return
+		return false
 	}
-	state, _ := tmp.MarshalJSONModel()
-	if err := nn.UnmarshalJSONModel(state); err != nil {
-		fmt.Printf("❌ UnmarshalJSONModel failed: %v\n", err)
-		return
+	rs, err := os.Stat(p)
+	if err != nil {
+		return false
+	}
+	ms, err := os.Stat(modelPath)
+	if err != nil {
+		return false
+	}
+	return rs.ModTime().After(ms.ModTime())
+}
+
+func saveEvalResult(name string, res EvalResult) error {
+	recordEvalToDB(res)
+	p, err := evalResultPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	if err := writeJSON(p, res); err != nil {
+		return err
+	}
+	if res.runCtx != nil {
+		return writeRunContext(p, *res.runCtx)
+	}
+	return nil
+}
+
+func evaluateModelADHD(modelPath string, skipGPU bool) (EvalResult, error) {
+	rc := captureRunContext()
+	rc.Operation = "evaluate"
+	res := EvalResult{Model: filepath.Base(modelPath), runCtx: &rc}
+
+	// Load dataset
+	images, labels, err := getMNIST()
+	if err != nil {
+		return res, fmt.Errorf("failed to load MNIST: %w", err)
+	}
+	trainInputs, trainTargets, testInputs, testTargets := splitMNIST(images, labels)
+	res.Split = splitLabel()
+	fmt.Printf("📚 Split: %s (train %d / test %d)\n", res.Split, len(trainInputs), len(testInputs))
+
+	// Load saved network
+	loaded, err := paragon.LoadNamedNetworkFromJSONFile(modelPath)
+	if err != nil {
+		return res, fmt.Errorf("load failed: %w", err)
+	}
+
+	// Rebuild fresh network with correct shapes/acts
+	nn, err := rebuildFloat32(loaded)
+	if err != nil {
+		return res, fmt.Errorf("skipping: %w", err)
 	}
 
 	// Initialize GPU
-	nn.WebGPUNative = true
-	startGPU := time.Now()
-	if err := nn.InitializeOptimizedGPU(); err != nil {
-		fmt.Printf("⚠️ WebGPU init failed: %v\n   Falling back to CPU.\n", err)
+	if skipGPU {
+		fmt.Println("ℹ️  CPU only: skipping WebGPU init.")
 		nn.WebGPUNative = false
 	} else {
-		fmt.Println("✅ WebGPU initialized successfully")
-		// Warm-up forward
-		if len(trainInputs) > 0 {
-			nn.Forward(trainInputs[0])
-			_ = nn.ExtractOutput()
+		nn.WebGPUNative = true
+		startGPU := time.Now()
+		if gnn, release, err := initGPUNet(nn); err != nil {
+			warnGPUFallback("evaluation", err)
+			nn.WebGPUNative = false
+			res.GPUFallback = true
+		} else {
+			nn = gnn
+			defer release()
+			fmt.Println("✅ WebGPU initialized successfully")
+			// Warm-up forward
+			if len(trainInputs) > 0 {
+				nn.Forward(trainInputs[0])
+				_ = nn.ExtractOutput()
+			}
+		}
+		fmt.Printf("⏱ WebGPU Init Time: %v\n", time.Since(startGPU))
+		recordTiming("init", time.Since(startGPU))
+	}
+	res.GPU = nn.WebGPUNative
+
+	// Run ADHD evaluation. On CPU the two sets are independent, so score them
+	// concurrently on separate networks; the GPU is a single device, so it
+	// keeps the sequential path.
+	var clone *paragon.Network[float32]
+	if !nn.WebGPUNative {
+		if clone, err = rebuildFloat32(loaded); err != nil {
+			fmt.Printf("⚠️  Could not clone network, evaluating sequentially: %v\n", err)
+			clone = nil
+		}
+	}
+	if clone != nil {
+		fmt.Println("🧪 Evaluating training and test sets concurrently (CPU)...")
+		start := time.Now()
+		var train, test evalSetOutcome
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() { defer wg.Done(); train = scoreNetwork(nn, trainInputs, trainTargets) }()
+		go func() { defer wg.Done(); test = scoreNetwork(clone, testInputs, testTargets) }()
+		wg.Wait()
+		recordTiming("eval", time.Since(start))
+
+		res.TrainScore = printEvalOutcome(train, trainInputs, "Train")
+		res.TestScore = printEvalOutcome(test, testInputs, "Test")
+		res.TrainLoss, res.TestLoss = train.Loss, test.Loss
+		fmt.Printf("⏱ Evaluate Time (wall, both sets): %v\n", time.Since(start))
+	} else {
+		fmt.Println("🧪 Evaluating on training set...")
+		train := evaluateFullNetwork(nn, trainInputs, trainTargets, "Train")
+
+		fmt.Println("\n🧪 Evaluating on test set...")
+		test := evaluateFullNetwork(nn, testInputs, testTargets, "Test")
+		res.TrainScore, res.TestScore = train.Perf.Score, test.Perf.Score
+		res.TrainLoss, res.TestLoss = train.Loss, test.Loss
+	}
+	res.LossMetric = lossMetric()
+	res.EvaluatedAt = time.Now().UTC()
+
+	if logitsEnabled() {
+		if p, err := dumpCalibrationLogits(nn, modelRelName(modelPath), testInputs, testTargets); err != nil {
+			fmt.Printf("⚠️  Could not dump logits: %v\n", err)
+		} else {
+			fmt.Printf("💾 Test-set logits → %s\n", p)
+		}
+	}
+
+	fmt.Printf("\n✅ Evaluation complete.\nTrain Score: %.4f%% | Test Score: %.4f%%\n", res.TrainScore, res.TestScore)
+	return res, nil
+}
+
+// evalOffender is one misclassified sample, ranked by how confident the
+// network was in its wrong answer.
+type evalOffender struct {
+	Pos        int     // position within the evaluated set
+	DatasetIdx int     // index in the combined MNIST dataset (-1 if unknown)
+	Label      int     // ground truth
+	Pred       int     // predicted class
+	Confidence float64 // output value of the (wrong) predicted class
+}
+
+const topOffenders = 10
+
+// evaluateFullNetwork scores and prints one set.
+func evaluateFullNetwork[T paragon.Numeric](nn *paragon.Network[T], inputs, targets [][][]float64, dataset string) evalSetOutcome {
+	o := scoreNetwork(nn, inputs, targets)
+	recordTiming("eval", o.Elapsed)
+	printEvalOutcome(o, inputs, dataset)
+	return o
+}
+
+// --loss reports a continuous loss next to the ADHD score, computed from the
+// raw outputs of the same forward passes: small improvements show up here
+// long before they flip an argmax.
+var flagLoss = flag.String("loss", "", "Also report a loss over eval sets: xent (cross-entropy) or mse")
+
+// lossMetric is the validated --loss value, or "" for none.
+func lossMetric() string {
+	if !flag.Parsed() {
+		return ""
+	}
+	switch m := strings.ToLower(strings.TrimSpace(*flagLoss)); m {
+	case "xent", "mse":
+		return m
+	case "", "none":
+		return ""
+	default:
+		lossWarnOnce.Do(func() { fmt.Printf("⚠️  Unknown --loss %q (want xent or mse); no loss reported\n", *flagLoss) })
+		return ""
+	}
+}
+
+var lossWarnOnce sync.Once
+
+// lossEpsilon keeps log() finite when a softmax output is exactly 0.
+const lossEpsilon = 1e-12
+
+// lossAccum averages a per-sample loss. The zero metric ("") ignores samples.
+type lossAccum struct {
+	metric string
+	sum    float64
+	n      int
+}
+
+func newLossAccum(metric string) *lossAccum { return &lossAccum{metric: metric} }
+
+// add folds in one sample's output vector against its (one-hot or smoothed)
+// target. Cross-entropy assumes the outputs are probabilities (softmax).
+func (a *lossAccum) add(out, target []float64) {
+	if a.metric == "" || len(out) != len(target) {
+		return
+	}
+	var l float64
+	switch a.metric {
+	case "xent":
+		for i, t := range target {
+			if t > 0 {
+				l -= t * math.Log(math.Max(out[i], lossEpsilon))
+			}
+		}
+	case "mse":
+		for i, t := range target {
+			d := out[i] - t
+			l += d * d
 		}
-		defer nn.CleanupOptimizedGPU()
+		l /= float64(len(out))
 	}
-	fmt.Printf("⏱ WebGPU Init Time: %v\n", time.Since(startGPU))
+	a.sum += l
+	a.n++
+}
 
-	// Run ADHD evaluation
-	fmt.Println("🧪 Evaluating on training set...")
-	trainScore := evaluateFullNetwork(nn, trainInputs, trainTargets, "Train")
+func (a *lossAccum) value() float64 { return safeDiv(a.sum, float64(a.n)) }
 
-	fmt.Println("\n🧪 Evaluating on test set...")
-	testScore := evaluateFullNetwork(nn, testInputs, testTargets, "Test")
+// formatLosses is the "  xent: train=… test=…" suffix for progress lines,
+// or "" without --loss.
+func formatLosses(train, test float64) string {
+	m := lossMetric()
+	if m == "" {
+		return ""
+	}
+	return fmt.Sprintf("  %s: train=%.5f test=%.5f", m, train, test)
+}
 
-	fmt.Printf("\n✅ Evaluation complete.\nTrain Score: %.4f%% | Test Score: %.4f%%\n", trainScore, testScore)
+// evalSetOutcome is the result of scoring one partition, kept separate from
+// printing so concurrent runs don't interleave their output.
+type evalSetOutcome struct {
+	Perf      *paragon.ADHDPerformance
+	Offenders []evalOffender
+	Elapsed   time.Duration
+	Loss      float64 // --loss over the set; 0 when no metric is selected
 }
 
-func evaluateFullNetwork[T paragon.Numeric](nn *paragon.Network[T], inputs, targets [][][]float64, dataset string) float64 {
+// scoreNetwork runs a forward pass over every sample and computes ADHD
+// metrics. It touches only nn, so two calls on different networks are safe
+// to run in parallel.
+func scoreNetwork[T paragon.Numeric](nn *paragon.Network[T], inputs, targets [][][]float64) evalSetOutcome {
 	start := time.Now()
 	expected := make([]float64, len(inputs))
 	actual := make([]float64, len(inputs))
+	var offenders []evalOffender
+	loss := newLossAccum(lossMetric())
 
 	for i := range inputs {
 		nn.Forward(inputs[i])     // runs on GPU if enabled
 		out := nn.ExtractOutput() // fetch prediction
-		expected[i] = float64(paragon.ArgMax(targets[i][0]))
-		actual[i] = float64(paragon.ArgMax(out))
+		loss.add(out, targets[i][0])
+		label, pred := paragon.ArgMax(targets[i][0]), paragon.ArgMax(out)
+		expected[i] = float64(label)
+		actual[i] = float64(pred)
+		if pred != label {
+			offenders = append(offenders, evalOffender{
+				Pos: i, DatasetIdx: -1, Label: label, Pred: pred, Confidence: out[pred],
+			})
+		}
 	}
 
 	nn.EvaluateModel(expected, actual)
-	score := nn.Performance.Score
+	return evalSetOutcome{Perf: nn.Performance, Offenders: offenders, Elapsed: time.Since(start), Loss: loss.value()}
+}
 
-	// Print ADHD metrics
+// printEvalOutcome prints the ADHD metrics for one set and returns its score.
+func printEvalOutcome(o evalSetOutcome, inputs [][][]float64, dataset string) float64 {
+	perf := o.Perf
 	fmt.Printf("\n📈 ADHD Performance (%s Set):\n", dataset)
-	for name, bucket := range nn.Performance.Buckets {
-		fmt.Printf("- %s: %d samples (%.2f%%)\n", name, bucket.Count, float64(bucket.Count)/float64(nn.Performance.Total)*100)
+	for name, bucket := range perf.Buckets {
+		fmt.Printf("- %s: %d samples (%.2f%%)\n", name, bucket.Count, float64(bucket.Count)/float64(perf.Total)*100)
+	}
+	fmt.Printf("- Total Samples: %d\n", perf.Total)
+	fmt.Printf("- Failures (100%%+): %d (%.2f%%)\n", perf.Failures, float64(perf.Failures)/float64(perf.Total)*100)
+	fmt.Printf("- Score: %.4f%%\n", perf.Score)
+	if m := lossMetric(); m != "" {
+		fmt.Printf("- Loss (%s): %.6f\n", m, o.Loss)
 	}
-	fmt.Printf("- Total Samples: %d\n", nn.Performance.Total)
-	fmt.Printf("- Failures (100%%+): %d (%.2f%%)\n", nn.Performance.Failures, float64(nn.Performance.Failures)/float64(nn.Performance.Total)*100)
-	fmt.Printf("- Score: %.4f%%\n", score)
-	fmt.Printf("⏱ Evaluate Time (%s): %v\n", dataset, time.Since(start))
+	fmt.Printf("⏱ Evaluate Time (%s): %v\n", dataset, o.Elapsed)
 
-	return score
+	printTopOffenders(o.Offenders, inputs, dataset)
+
+	return perf.Score
+}
+
+// printTopOffenders lists the most confidently-wrong samples so there is
+// something concrete to look at beyond the failure count.
+func printTopOffenders(offenders []evalOffender, inputs [][][]float64, dataset string) {
+	if len(offenders) == 0 {
+		return
+	}
+	sort.Slice(offenders, func(i, j int) bool { return offenders[i].Confidence > offenders[j].Confidence })
+	if len(offenders) > topOffenders {
+		offenders = offenders[:topOffenders]
+	}
+
+	fmt.Printf("🔎 Top %d offenders (%s Set, most confident wrong predictions):\n", len(offenders), dataset)
+	for _, o := range offenders {
+		o.DatasetIdx = mnistIndexOf(inputs[o.Pos])
+		png := mnistPNGPath(o.DatasetIdx, o.Label)
+		if png == "" {
+			png = "(not exported — run option 3)"
+		}
+		fmt.Printf("   idx=%-6d true=%d pred=%d conf=%.4f  %s\n", o.DatasetIdx, o.Label, o.Pred, o.Confidence, png)
+	}
 }
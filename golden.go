@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openfluke/paragon/v3"
+)
+
+// A golden file pins a model's exact CPU outputs on the fixed digit samples,
+// so a later run — typically after a paragon upgrade — can be checked
+// against it. Outputs are stored at full float64 precision.
+type GoldenFile struct {
+	Model          string         `json:"model"`
+	ModelSHA256    string         `json:"model_sha256"`
+	CapturedAt     time.Time      `json:"captured_at"`
+	Build          BuildInfo      `json:"build"`
+	ParagonVersion string         `json:"paragon_version"`
+	Samples        []GoldenSample `json:"samples"`
+}
+
+// GoldenSample is one digit's input and reference output.
+type GoldenSample struct {
+	Digit       int       `json:"digit"`
+	Idx         int       `json:"idx"`          // index into the combined MNIST set
+	InputSHA256 string    `json:"input_sha256"` // catches a different MNIST file behind the same index
+	Pred        int       `json:"pred"`
+	Output      []float64 `json:"output"`
+}
+
+// paragonVersion is the paragon module version this binary was built with,
+// falling back to paragon's own constant when build info is unavailable.
+func paragonVersion() string {
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, d := range bi.Deps {
+			if strings.HasPrefix(d.Path, "github.com/openfluke/paragon") {
+				if d.Replace != nil {
+					return d.Replace.Version
+				}
+				return d.Version
+			}
+		}
+	}
+	return paragon.Version
+}
+
+// inputSHA256 hashes a sample's pixels.
+func inputSHA256(img [][]float64) string {
+	h := sha256.New()
+	var buf [8]byte
+	for _, row := range img {
+		for _, v := range row {
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+			h.Write(buf[:])
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// defaultGoldenPath is public/golden/<model>.golden.json.
+func defaultGoldenPath(modelPath string) (string, error) {
+	dir, err := EnsurePublicDir("golden")
+	if err != nil {
+		return "", err
+	}
+	base := strings.TrimSuffix(filepath.Base(modelPath), filepath.Ext(modelPath))
+	return filepath.Join(dir, base+".golden.json"), nil
+}
+
+// captureGolden runs the model at modelPath on the CPU over the fixed digit
+// samples and writes the outputs to out.
+func captureGolden(modelPath, out string) error {
+	images, labels, err := getMNIST()
+	if err != nil {
+		return fmt.Errorf("load mnist: %w", err)
+	}
+	sel, err := sampleIndexPerDigit(labels)
+	if err != nil {
+		return err
+	}
+	nn, err := loadFloat32Model(modelPath)
+	if err != nil {
+		return err
+	}
+	nn.WebGPUNative = false
+	sum, _, err := fileSHA256(modelPath)
+	if err != nil {
+		return err
+	}
+
+	g := GoldenFile{
+		Model:          filepath.Base(modelPath),
+		ModelSHA256:    sum,
+		CapturedAt:     time.Now().UTC(),
+		Build:          currentBuild(),
+		ParagonVersion: paragonVersion(),
+	}
+	for d := 0; d <= 9; d++ {
+		idx, ok := sel[d]
+		if !ok {
+			continue
+		}
+		nn.Forward(images[idx])
+		outp := nn.ExtractOutput()
+		g.Samples = append(g.Samples, GoldenSample{
+			Digit: d, Idx: idx, InputSHA256: inputSHA256(images[idx]),
+			Pred: argmax64(outp), Output: outp,
+		})
+	}
+	if len(g.Samples) == 0 {
+		return fmt.Errorf("no digit samples to capture")
+	}
+	return writeJSON(out, g)
+}
+
+// compareGolden re-runs the model at modelPath on the samples recorded in
+// goldenPath and checks every output against the reference. A sample passes
+// when its prediction is unchanged and no output moved by more than tol
+// (0 demands bit-identical outputs). Each sample is printed; the bool is
+// false if any failed. A changed model file or paragon version is reported
+// but doesn't fail the check by itself — that is usually the point.
+func compareGolden(modelPath, goldenPath string, tol float64) (bool, error) {
+	bz, err := os.ReadFile(goldenPath)
+	if err != nil {
+		return false, err
+	}
+	var g GoldenFile
+	if err := json.Unmarshal(bz, &g); err != nil {
+		return false, fmt.Errorf("parse %s: %w", filepath.Base(goldenPath), err)
+	}
+	if len(g.Samples) == 0 {
+		return false, fmt.Errorf("%s has no samples", filepath.Base(goldenPath))
+	}
+
+	images, _, err := getMNIST()
+	if err != nil {
+		return false, fmt.Errorf("load mnist: %w", err)
+	}
+	nn, err := loadFloat32Model(modelPath)
+	if err != nil {
+		return false, err
+	}
+	nn.WebGPUNative = false
+
+	if sum, _, err := fileSHA256(modelPath); err == nil && sum != g.ModelSHA256 {
+		fmt.Printf("ℹ️  Model file changed since capture (%s)\n", g.CapturedAt.Format(time.RFC3339))
+	}
+	if v := paragonVersion(); v != g.ParagonVersion {
+		fmt.Printf("ℹ️  paragon %s → %s\n", g.ParagonVersion, v)
+	}
+
+	pass := true
+	for _, s := range g.Samples {
+		if s.Idx < 0 || s.Idx >= len(images) {
+			fmt.Printf("   ❌ digit %d: idx %d is outside the loaded MNIST set (%d samples)\n", s.Digit, s.Idx, len(images))
+			pass = false
+			continue
+		}
+		if inputSHA256(images[s.Idx]) != s.InputSHA256 {
+			fmt.Printf("   ❌ digit %d (idx %d): input image differs from the captured one — different MNIST files?\n", s.Digit, s.Idx)
+			pass = false
+			continue
+		}
+		nn.Forward(images[s.Idx])
+		outp := nn.ExtractOutput()
+		if len(outp) != len(s.Output) {
+			fmt.Printf("   ❌ digit %d (idx %d): output length %d, golden %d\n", s.Digit, s.Idx, len(outp), len(s.Output))
+			pass = false
+			continue
+		}
+		maxAbs, mae := driftMaxAndMAE(s.Output, outp)
+		pred := argmax64(outp)
+		ok := pred == s.Pred && maxAbs <= tol
+		mark := "✅"
+		if !ok {
+			mark, pass = "❌", false
+		}
+		fmt.Printf("   %s digit %d (idx %d): pred %d (golden %d) max|Δ| %.3e mae %.3e\n",
+			mark, s.Digit, s.Idx, pred, s.Pred, maxAbs, mae)
+	}
+	return pass, nil
+}
+
+// runGoldenPrompt captures a golden file for modelPath or checks the model
+// against one.
+func runGoldenPrompt(reader *bufio.Reader, modelPath string) {
+	def, err := defaultGoldenPath(modelPath)
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	fmt.Print("Golden outputs: [c]apture or [k] check (default check if the file exists): ")
+	raw, _ := reader.ReadString('\n')
+	mode := strings.TrimSpace(strings.ToLower(raw))
+	if mode == "" {
+		mode = "c"
+		if _, err := os.Stat(def); err == nil {
+			mode = "k"
+		}
+	}
+
+	fmt.Printf("Golden file [default %s]: ", def)
+	pRaw, _ := reader.ReadString('\n')
+	path := strings.TrimSpace(pRaw)
+	if path == "" {
+		path = def
+	}
+
+	switch mode {
+	case "c", "capture":
+		if err := captureGolden(modelPath, path); err != nil {
+			fmt.Println("❌", err)
+			return
+		}
+		fmt.Printf("💾 Golden outputs written → %s\n", path)
+	case "k", "check":
+		fmt.Print("Tolerance (max |Δ| per output, 0 = bit-identical) [default 1e-6]: ")
+		tRaw, _ := reader.ReadString('\n')
+		tol := 1e-6
+		if s := strings.TrimSpace(tRaw); s != "" {
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil || v < 0 {
+				fmt.Println("❌ Invalid tolerance")
+				return
+			}
+			tol = v
+		}
+		ok, err := compareGolden(modelPath, path, tol)
+		if err != nil {
+			fmt.Println("❌", err)
+			return
+		}
+		if ok {
+			fmt.Printf("✅ %s matches %s (tol %.1e)\n", filepath.Base(modelPath), filepath.Base(path), tol)
+		} else {
+			fmt.Printf("❌ %s no longer matches %s (tol %.1e)\n", filepath.Base(modelPath), filepath.Base(path), tol)
+		}
+	default:
+		fmt.Println("❌ Invalid choice")
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// warnGPUFallback is the one loud message every GPU path prints when WebGPU
+// init fails and the "GPU" run silently becomes a CPU run. JSON outputs carry
+// the same fact as gpu_fallback: true.
+func warnGPUFallback(where string, err error) {
+	fmt.Println("⚠️  ────────────────────────────────────────────────────────────")
+	fmt.Printf("⚠️  GPU FALLBACK in %s: WebGPU init failed: %v\n", where, err)
+	fmt.Println("⚠️  Everything labelled GPU below actually ran on the CPU.")
+	fmt.Println("⚠️  ────────────────────────────────────────────────────────────")
+}
+
+// InitStats summarizes repeated InitializeOptimizedGPU/CleanupOptimizedGPU
+// cycles on one model. The first sample is usually the cold-driver outlier.
+type InitStats struct {
+	Model     string    `json:"model"`
+	Iters     int       `json:"iters"`
+	OK        int       `json:"ok"` // successful inits
+	MinMS     float64   `json:"min_ms"`
+	MedianMS  float64   `json:"median_ms"`
+	P95MS     float64   `json:"p95_ms"`
+	MaxMS     float64   `json:"max_ms"`
+	SamplesMS []float64 `json:"samples_ms"` // in run order
+	LastError string    `json:"last_error,omitempty"`
+}
+
+func (s InitStats) ToJSON() string {
+	bz, _ := json.MarshalIndent(s, "", "  ")
+	return string(bz)
+}
+
+// benchGPUInit repeatedly initializes and tears down WebGPU for modelPath and
+// reports the init time distribution. Only successful inits are sampled.
+func benchGPUInit(modelPath string, iters int) (InitStats, error) {
+	stats := InitStats{Model: modelPath, Iters: iters}
+	if cpuOnly() {
+		return stats, fmt.Errorf("%w: disabled by --cpu-only", ErrGPUInitFailed)
+	}
+	if iters < 1 {
+		return stats, fmt.Errorf("iters must be ≥ 1")
+	}
+
+	if err := requireWebGPU(); err != nil {
+		return stats, err
+	}
+	nn, err := loadFloat32Model(modelPath)
+	if err != nil {
+		return stats, err
+	}
+
+	for i := 0; i < iters; i++ {
+		nn.WebGPUNative, nn.Debug = true, false
+		start := time.Now()
+		if err := nn.InitializeOptimizedGPU(); err != nil {
+			stats.LastError = err.Error()
+			nn.WebGPUNative = false
+			continue
+		}
+		ms := float64(time.Since(start).Microseconds()) / 1000.0
+		nn.CleanupOptimizedGPU()
+
+		stats.SamplesMS = append(stats.SamplesMS, ms)
+		stats.OK++
+	}
+	if stats.OK == 0 {
+		return stats, fmt.Errorf("%w on all %d attempts: %s", ErrGPUInitFailed, iters, stats.LastError)
+	}
+
+	sorted := append([]float64(nil), stats.SamplesMS...)
+	sort.Float64s(sorted)
+	stats.MinMS = sorted[0]
+	stats.MedianMS = percentile(sorted, 50)
+	stats.P95MS = percentile(sorted, 95)
+	stats.MaxMS = sorted[len(sorted)-1]
+	return stats, nil
+}
+
+// percentile returns the nearest-rank p-th percentile of an ascending slice.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p/100*float64(len(sorted))+0.999999) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+func runGPUInitMenu() {
+	if cpuOnly() {
+		fmt.Println("❌ --cpu-only: the WebGPU init benchmark needs the GPU; run without --cpu-only.")
+		return
+	}
+	modelDir := ModelsDir()
+
+	models, _ := listModels(modelDir)
+	if len(models) == 0 {
+		fmt.Println("❌ No models found in public/models/")
+		return
+	}
+
+	fmt.Println("\nAvailable models:")
+	for i, m := range models {
+		fmt.Printf("%d) %s%s\n", i+1, m, modelChoiceLabel(modelDir, m))
+	}
+	fmt.Println("0) Back")
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Select model: ")
+	choiceRaw, _ := reader.ReadString('\n')
+	choice := strings.TrimSpace(choiceRaw)
+	if choice == "0" {
+		return
+	}
+	idx, err := strconv.Atoi(choice)
+	if err != nil || idx < 1 || idx > len(models) {
+		fmt.Println("❌ Invalid choice")
+		return
+	}
+
+	iters := 10
+	fmt.Printf("Iterations [default %d]: ", iters)
+	if s, _ := reader.ReadString('\n'); strings.TrimSpace(s) != "" {
+		if v, err := strconv.Atoi(strings.TrimSpace(s)); err == nil && v > 0 {
+			iters = v
+		}
+	}
+
+	fmt.Print("Output format [table/json] (default table): ")
+	fmtRaw, _ := reader.ReadString('\n')
+	outFmt := strings.TrimSpace(strings.ToLower(fmtRaw))
+	if outFmt == "" {
+		outFmt = "table"
+	}
+	if outFmt != "table" && outFmt != "json" {
+		fmt.Println("❌ Invalid format")
+		return
+	}
+
+	fmt.Print("Write JSON to file as well? (leave blank to skip): ")
+	outRaw, _ := reader.ReadString('\n')
+	outFile := strings.TrimSpace(outRaw)
+
+	modelPath := filepath.Join(modelDir, models[idx-1])
+	fmt.Printf("\n▶ Measuring WebGPU init for %s (%d iterations)…\n", models[idx-1], iters)
+	stats, err := benchGPUInit(modelPath, iters)
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+
+	if outFmt == "json" {
+		fmt.Println(stats.ToJSON())
+	} else {
+		fmt.Printf("WebGPU init (%d/%d ok): min=%.3fms median=%.3fms p95=%.3fms max=%.3fms\n",
+			stats.OK, stats.Iters, stats.MinMS, stats.MedianMS, stats.P95MS, stats.MaxMS)
+		if stats.LastError != "" {
+			fmt.Printf("⚠️  Some inits failed, last error: %s\n", stats.LastError)
+		}
+	}
+
+	if outFile != "" {
+		if err := os.WriteFile(outFile, []byte(stats.ToJSON()), 0o644); err != nil {
+			fmt.Printf("❌ Failed to write %s: %v\n", outFile, err)
+			return
+		}
+		fmt.Printf("💾 JSON written → %s\n", outFile)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openfluke/paragon/v3"
+)
+
+// --gpu-warm keeps one WebGPU-initialized network per topology resident for
+// the whole process. Evaluate, compare and telemetry pay InitializeOptimizedGPU
+// once per shape; later models with the same shape get their weights copied in
+// and synced to the existing device buffers instead.
+var flagGPUWarm = flag.Bool("gpu-warm", false, "Keep a WebGPU-initialized network per topology resident across operations (cleaned up on exit)")
+
+func gpuWarm() bool {
+	return flag.Parsed() && *flagGPUWarm
+}
+
+// warmGPUNet is one resident network. mu is held from initGPUNet until the
+// caller's release, so two operations never share the device buffers at once.
+type warmGPUNet struct {
+	mu sync.Mutex
+	nn *paragon.Network[float32]
+}
+
+var (
+	warmGPUMu   sync.Mutex
+	warmGPUNets = map[string]*warmGPUNet{}
+)
+
+// topologyKey identifies networks whose GPU buffers are interchangeable:
+// same layer shapes, per-neuron activations and connection counts. Each
+// layer's activations are run-length encoded in neuron order ("relu*16").
+func topologyKey(nn *paragon.Network[float32]) string {
+	var b strings.Builder
+	for li, layer := range nn.Layers {
+		var acts []string
+		run, conns := 0, 0
+		last := ""
+		flush := func() {
+			if run > 0 {
+				acts = append(acts, fmt.Sprintf("%s*%d", last, run))
+			}
+		}
+		for _, row := range layer.Neurons {
+			for _, neuron := range row {
+				if neuron == nil {
+					continue
+				}
+				if run > 0 && neuron.Activation != last {
+					flush()
+					run = 0
+				}
+				last = neuron.Activation
+				run++
+				conns += len(neuron.Inputs)
+			}
+		}
+		flush()
+		if li > 0 {
+			b.WriteByte('|')
+		}
+		fmt.Fprintf(&b, "%dx%d:%s:%d", layer.Width, layer.Height, strings.Join(acts, ","), conns)
+	}
+	return b.String()
+}
+
+// copyWeights copies biases and connection weights from src into dst, which
+// must share src's topology.
+func copyWeights(dst, src *paragon.Network[float32]) {
+	for li := 1; li < len(src.Layers); li++ {
+		for y, row := range src.Layers[li].Neurons {
+			for x, neuron := range row {
+				if neuron == nil {
+					continue
+				}
+				d := dst.Layers[li].Neurons[y][x]
+				d.Bias = neuron.Bias
+				for k := range neuron.Inputs {
+					d.Inputs[k].Weight = neuron.Inputs[k].Weight
+				}
+			}
+		}
+	}
+}
+
+// initGPUNet initializes nn for WebGPU and returns the network to run forwards
+// on plus a release func the caller must defer. Without --gpu-warm that is nn
+// itself and release cleans it up. With --gpu-warm the first network of each
+// topology stays resident; later calls copy nn's weights into it and sync them
+// to the device, and release only unlocks it. The returned network is nn
+// itself unless a resident one was reused.
+func initGPUNet(nn *paragon.Network[float32]) (*paragon.Network[float32], func(), error) {
+	if err := requireWebGPU(); err != nil {
+		return nn, func() {}, err
+	}
+	if !gpuWarm() {
+		if err := nn.InitializeOptimizedGPU(); err != nil {
+			return nn, func() {}, err
+		}
+		return nn, nn.CleanupOptimizedGPU, nil
+	}
+
+	key := topologyKey(nn)
+	warmGPUMu.Lock()
+	w := warmGPUNets[key]
+	if w == nil {
+		w = &warmGPUNet{}
+		warmGPUNets[key] = w
+	}
+	warmGPUMu.Unlock()
+
+	w.mu.Lock()
+	if w.nn == nil {
+		if err := nn.InitializeOptimizedGPU(); err != nil {
+			w.mu.Unlock()
+			return nn, func() {}, err
+		}
+		w.nn = nn
+		return nn, w.mu.Unlock, nil
+	}
+
+	copyWeights(w.nn, nn)
+	var err error
+	// SyncCPUWeightsToGPU prints a line per layer; keep the menus readable.
+	withSilencedStdout(func() { err = w.nn.SyncCPUWeightsToGPU() })
+	if err != nil {
+		w.mu.Unlock()
+		return nn, func() {}, fmt.Errorf("warm GPU sync: %w", err)
+	}
+	w.nn.WebGPUNative = true
+	fmt.Println("♨️  Reusing warm WebGPU context")
+	return w.nn, w.mu.Unlock, nil
+}
+
+// releaseWarmGPU cleans up every resident network. Called on exit.
+func releaseWarmGPU() {
+	warmGPUMu.Lock()
+	defer warmGPUMu.Unlock()
+	keys := make([]string, 0, len(warmGPUNets))
+	for k := range warmGPUNets {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		w := warmGPUNets[k]
+		w.mu.Lock()
+		if w.nn != nil {
+			w.nn.CleanupOptimizedGPU()
+			w.nn = nil
+		}
+		w.mu.Unlock()
+		delete(warmGPUNets, k)
+	}
+}
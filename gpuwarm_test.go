@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+// TestTopologyKeySeesEveryActivation checks two networks that differ only in
+// a later neuron's activation don't share a resident GPU network.
+func TestTopologyKeySeesEveryActivation(t *testing.T) {
+	spec := ModelSpec{ID: "T", Layers: []string{"784", "16", "10"}}
+	a, err := buildSpecNetwork(spec)
+	if err != nil {
+		t.Fatalf("buildSpecNetwork: %v", err)
+	}
+	b, err := buildSpecNetwork(spec)
+	if err != nil {
+		t.Fatalf("buildSpecNetwork: %v", err)
+	}
+	if topologyKey(a) != topologyKey(b) {
+		t.Fatalf("same spec, different keys:\n%s\n%s", topologyKey(a), topologyKey(b))
+	}
+	b.Layers[1].Neurons[0][7].Activation = "tanh"
+	if topologyKey(a) == topologyKey(b) {
+		t.Fatalf("neuron 7's activation is not part of the key: %s", topologyKey(a))
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// HostProbe is a quick read on the link to a model host, taken before the
+// telemetry pipeline commits to downloading the whole zoo.
+type HostProbe struct {
+	ManifestLatency time.Duration `json:"manifest_latency"`
+	SampleFile      string        `json:"sample_file"`
+	SampleBytes     int64         `json:"sample_bytes"`
+	BytesPerSec     float64       `json:"bytes_per_sec"`
+	ZooFiles        int           `json:"zoo_files"`
+	ZooBytes        int64         `json:"zoo_bytes"` // from manifest sizes; 0 if the host doesn't record them
+	ETA             time.Duration `json:"eta"`
+}
+
+// probeHost times a manifest fetch and the download of the smallest model in
+// it, then extrapolates how long the full zoo would take at that rate.
+func probeHost(hostBase string) (HostProbe, error) {
+	var p HostProbe
+
+	start := time.Now()
+	manifest, err := fetchManifest(hostBase)
+	if err != nil {
+		return p, fmt.Errorf("fetch manifest: %w", err)
+	}
+	p.ManifestLatency = time.Since(start)
+	if len(manifest) == 0 {
+		return p, fmt.Errorf("%w at %s", ErrManifestEmpty, hostBase)
+	}
+
+	var sample *modelManifest
+	for i := range manifest {
+		m := &manifest[i]
+		if m.Filename == "" || !safeModelRelPath(m.Filename) {
+			continue
+		}
+		p.ZooFiles++
+		p.ZooBytes += m.Bytes
+		if sample == nil || m.Bytes < sample.Bytes {
+			sample = m
+		}
+	}
+	if sample == nil {
+		return p, fmt.Errorf("manifest at %s lists no usable models", hostBase)
+	}
+	p.SampleFile = sample.Filename
+
+	url := strings.TrimRight(hostBase, "/") + "/models/" + strings.TrimLeft(filepath.ToSlash(sample.Filename), "/")
+	client := &http.Client{Timeout: 60 * time.Second}
+	start = time.Now()
+	resp, err := telemetryGet(client, url)
+	if err != nil {
+		return p, fmt.Errorf("download %s: %w", sample.Filename, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return p, fmt.Errorf("download %s: status %s", sample.Filename, resp.Status)
+	}
+	n, err := io.Copy(io.Discard, resp.Body)
+	if err != nil {
+		return p, fmt.Errorf("download %s: %w", sample.Filename, err)
+	}
+	elapsed := time.Since(start)
+	p.SampleBytes = n
+
+	// The sample includes one round trip; subtract the manifest latency as a
+	// rough RTT so tiny files don't make the link look slower than it is.
+	xfer := elapsed - p.ManifestLatency
+	if xfer <= 0 {
+		xfer = elapsed
+	}
+	if xfer > 0 {
+		p.BytesPerSec = float64(n) / xfer.Seconds()
+	}
+
+	if p.ZooBytes == 0 {
+		// Old manifests carry no sizes: assume every file is like the sample.
+		p.ZooBytes = n * int64(p.ZooFiles)
+	}
+	if p.BytesPerSec > 0 {
+		p.ETA = time.Duration(float64(p.ZooBytes)/p.BytesPerSec*float64(time.Second)) +
+			time.Duration(p.ZooFiles)*p.ManifestLatency
+	}
+	return p, nil
+}
+
+func (p HostProbe) Print() {
+	fmt.Printf("📡 Manifest latency: %v\n", p.ManifestLatency.Round(time.Millisecond))
+	fmt.Printf("📡 Throughput: %.2f MB/s (%s, %d bytes)\n", p.BytesPerSec/1e6, p.SampleFile, p.SampleBytes)
+	fmt.Printf("📦 Zoo: %d file(s), %.1f MB → estimated download %v\n",
+		p.ZooFiles, float64(p.ZooBytes)/1e6, p.ETA.Round(time.Second))
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/openfluke/paragon/v3"
+)
+
+// Concurrency model for /infer:
+//
+//   - Inference runs on the CPU only. The server never creates WebGPU
+//     contexts, so concurrent requests can't contend for the device.
+//   - Each model is loaded once into a cachedModel. paragon's Forward writes
+//     neuron values in place, so a network is not safe for concurrent use;
+//     every Forward+ExtractOutput pair runs under that model's mutex.
+//     Requests for different models proceed in parallel.
+//   - The cache lock (inferCache.mu) is held only for map lookups. Loading a
+//     model happens under the entry's own lock, so a slow load of a big model
+//     doesn't stall requests for models that are already warm.
+//   - If the model file changes on disk (retrain, reset) the entry reloads on
+//     the next request.
+type cachedModel struct {
+	mu      sync.Mutex
+	nn      *paragon.Network[float32]
+	modTime time.Time
+}
+
+var inferCache = struct {
+	mu     sync.Mutex
+	models map[string]*cachedModel
+}{models: map[string]*cachedModel{}}
+
+// InferRequest is the body of POST /infer. Input is a 28×28 image (values
+// 0..1); a flat 784-value row is reshaped.
+type InferRequest struct {
+	Model string      `json:"model"` // relative to public/models, e.g. "mnist_S1.json"
+	Input [][]float64 `json:"input"`
+}
+
+type InferResponse struct {
+	Model     string    `json:"model"`
+	Pred      int       `json:"pred"`
+	Output    []float64 `json:"output"`
+	ElapsedMS float64   `json:"elapsed_ms"`
+}
+
+// RegisterInfer mounts POST /infer, serving models from baseDir/models.
+func RegisterInfer(app *fiber.App, baseDir string) {
+	modelDir := filepath.Join(baseDir, "models")
+
+	app.Post("/infer", func(c *fiber.Ctx) error {
+		var req InferRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid JSON body: " + err.Error()})
+		}
+		if !safeModelRelPath(req.Model) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid model name"})
+		}
+		input, err := inferInput(req.Input)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		res, err := inferCached(filepath.Join(modelDir, filepath.FromSlash(req.Model)), input)
+		if err != nil {
+			status := fiber.StatusInternalServerError
+			if os.IsNotExist(err) {
+				status = fiber.StatusNotFound
+			}
+			return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+		}
+		res.Model = req.Model
+		return c.JSON(res)
+	})
+}
+
+// inferInput validates the request image and reshapes a flat 784 row to 28×28.
+func inferInput(in [][]float64) ([][]float64, error) {
+	if len(in) == 1 && len(in[0]) == 28*28 {
+		img := make([][]float64, 28)
+		for r := range img {
+			img[r] = in[0][r*28 : (r+1)*28]
+		}
+		return img, nil
+	}
+	if len(in) != 28 {
+		return nil, fmt.Errorf("input must be 28x28 (or 1x784), got %d rows", len(in))
+	}
+	for r, row := range in {
+		if len(row) != 28 {
+			return nil, fmt.Errorf("input row %d has %d values, want 28", r, len(row))
+		}
+	}
+	return in, nil
+}
+
+// inferCached runs one forward on the cached network for modelPath.
+func inferCached(modelPath string, input [][]float64) (InferResponse, error) {
+	st, err := os.Stat(modelPath)
+	if err != nil {
+		return InferResponse{}, err
+	}
+
+	inferCache.mu.Lock()
+	m, ok := inferCache.models[modelPath]
+	if !ok {
+		m = &cachedModel{}
+		inferCache.models[modelPath] = m
+	}
+	inferCache.mu.Unlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.nn == nil || !st.ModTime().Equal(m.modTime) {
+		nn, err := loadFloat32Model(modelPath)
+		if err != nil {
+			return InferResponse{}, err
+		}
+		nn.WebGPUNative = false
+		m.nn, m.modTime = nn, st.ModTime()
+	}
+
+	start := time.Now()
+	m.nn.Forward(input)
+	out := m.nn.ExtractOutput()
+	return InferResponse{
+		Pred:      argmax64(out),
+		Output:    roundSlice(out, 6),
+		ElapsedMS: float64(time.Since(start).Microseconds()) / 1000.0,
+	}, nil
+}
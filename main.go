@@ -3,9 +3,14 @@ package main
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,10 +20,50 @@ import (
 )
 
 func main() {
+	// Flags come first (e.g. --max-samples 2000 9); the first positional arg is the menu choice.
+	flag.Parse()
+	setupNDJSON()
+
+	if *flagProbeTimeout <= 0 {
+		fmt.Printf("⚠️  --probe-timeout must be positive; using %s\n", probeTimeout)
+	}
+	SetProbeTimeout(*flagProbeTimeout)
+
+	if *flagDescribe {
+		fmt.Println(describeCommands())
+		return
+	}
+
+	if n := maxSamples(); n > 0 {
+		fmt.Printf("⚠️  MNIST capped to the first %d samples — results are NOT comparable to full runs.\n", n)
+	}
+
+	// Non-interactive batch: iso-demo batch ops.json
+	if flag.Arg(0) == "batch" {
+		if flag.NArg() < 2 {
+			fmt.Println("usage: iso-demo [flags] batch <ops.json>")
+			os.Exit(2)
+		}
+		err := runBatchFile(flag.Arg(1))
+		shutdown()
+		if err != nil {
+			fmt.Println("❌ Batch failed:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// If a number is passed on the command line, run it directly
-	if len(os.Args) > 1 {
-		choice := strings.TrimSpace(os.Args[1])
-		runChoice(choice)
+	if flag.NArg() > 0 {
+		choice := strings.TrimSpace(flag.Arg(0))
+		err := runChoice(choice)
+		shutdown()
+		switch {
+		case errors.Is(err, errUnknownCommand):
+			os.Exit(2)
+		case err != nil:
+			os.Exit(1)
+		}
 		return
 	}
 
@@ -27,88 +72,73 @@ func main() {
 	for {
 		fmt.Println()
 		fmt.Println("=== Paragon ISO Demo ===")
-		fmt.Println("1) Show computer info (JSON)")
-		fmt.Println("2) Run MNIST experiment (download/train/test via PILOT)")
-		fmt.Println("3) Export MNIST images to PNG (public/mnist_png/all)")
-		fmt.Println("4) Create the models for testing")
-		fmt.Println("5) Benchmark models CPU on digit samples 1 item of each number (1 to 9)")
-		fmt.Println("6) Benchmark models GPU on digit samples 1 item of each number (1 to 9)")
-		fmt.Println("7) Compare CPU vs GPU (choose model)")
-		fmt.Println("8) Train model(s): N epochs or until target ADHD%")
-		fmt.Println("9) Evaluate a model on Train/Test set (ADHD metrics)")
-		fmt.Println("10) Run CPU numeric microbench (duration/filter/format)")
-		fmt.Println("11) Web server: start/stop/status")
-		fmt.Println("12) Telemetry: pull models from host → run → push report")
+		printMenu()
 
 		fmt.Println("0) Exit")
 		fmt.Print("Select: ")
 
 		choiceRaw, _ := reader.ReadString('\n')
 		choice := strings.TrimSpace(choiceRaw)
-		runChoice(choice)
+		_ = runChoice(choice)
 	}
 }
 
-func runChoice(choice string) {
-	switch choice {
-	case "1":
-		doShowInfo()
-	case "2":
-		doRunExperiment()
-	case "3":
-		doExportPNGs()
-	case "4":
-		createModelZoo()
-	case "5":
-		benchmarkModelsOnDigits(false)
-	case "6":
-		benchmarkModelsOnDigits(true)
-	case "7":
-		runCompareMenu()
-	case "8":
-		runTrainMenu()
-	case "9":
-		runEvaluateMenu()
-	case "10":
-		runBenchMenu()
-	case "11":
-		runWebMenu()
-	case "12":
-		runTelemetryMenu()
-
-	case "0":
+// runChoice dispatches one menu code through the command registry. The
+// error is what a one-shot `iso-demo <code>` turns into its exit status.
+func runChoice(choice string) error {
+	if cmd, ok := findCommand(choice); ok {
+		if cmd.NeedsMNIST {
+			if err := ensureMNISTOrGuide(); err != nil {
+				return err
+			}
+		}
+		return cmd.Run()
+	}
+	if choice == "0" {
+		shutdown()
 		fmt.Println("Bye.")
 		os.Exit(0)
-	default:
-		fmt.Println("Unknown option. Please choose 0, 1, 2, or 3.")
 	}
+	fmt.Printf("Unknown option. Please choose %s.\n", commandCodes())
+	return errUnknownCommand
+}
+
+// shutdown runs the on-exit hooks: the --timings dump and releasing any
+// --gpu-warm contexts.
+func shutdown() {
+	dumpTimings()
+	releaseWarmGPU()
 }
 
 func doShowInfo() {
-	info := Collect()
+	info := withWebGPUProbe(Collect())
 	fmt.Println(info.ToJSON())
 }
 
-func doRunExperiment() {
+func doRunExperiment() error {
 	fmt.Println("🚀 Launching PILOT MNIST experiment…")
 	start := time.Now()
-	if err := runPilotMNIST(); err != nil {
+	err := runPilotMNIST()
+	// PILOT may have (re)downloaded the IDX files; drop any cached copy.
+	invalidateMNIST()
+	if err != nil {
 		fmt.Println("❌ Experiment failed:", err)
-		return
+		return err
 	}
 	fmt.Printf("✅ Experiment completed in %v\n", time.Since(start))
+	return nil
 }
 
-func doExportPNGs() {
+func doExportPNGs() error {
 	mnistDir := MustPublicPath("mnist")
 	fmt.Printf("📂 MNIST directory: %s\n", mnistDir)
 
 	startData := time.Now()
-	images, labels, err := loadMNISTData(mnistDir)
+	images, labels, err := getMNIST()
 	if err != nil {
 		fmt.Println("❌ Failed to load MNIST from", mnistDir, "--- run option 2 first to download.")
 		fmt.Println("Error:", err)
-		return
+		return err
 	}
 	loadT := time.Since(startData)
 
@@ -119,10 +149,11 @@ func doExportPNGs() {
 	startExport := time.Now()
 	if err := exportMNISTAsPNGs(images, labels, "all"); err != nil {
 		fmt.Println("❌ PNG export failed:", err)
-		return
+		return err
 	}
 	fmt.Printf("✅ Exported %d images to %s in %v\n",
 		len(images), filepath.Join("public", "mnist_png", "all"), time.Since(startExport))
+	return nil
 }
 
 // --- Existing experiment launcher (kept from your code) ---
@@ -133,47 +164,127 @@ func runPilotMNIST() error {
 }
 
 func runCompareMenu() {
-	modelDir := MustPublicPath("models")
+	modelDir := ModelsDir()
 
 	// list models
-	entries, _ := os.ReadDir(modelDir)
-	models := []string{}
-	for _, e := range entries {
-		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") || e.Name() == "manifest.json" {
-			continue
-		}
-		models = append(models, e.Name())
-	}
+	// list models (recursive, relative paths like "exp1/mnist_S1.json")
+	models, _ := listModels(modelDir)
 
 	if len(models) == 0 {
 		fmt.Println("❌ No models found in public/models/")
 		return
 	}
 
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Println("\nCompare what?")
+	fmt.Println("1) One model: CPU vs GPU")
+	fmt.Println("2) Two models: A vs B (both on CPU)")
+	fmt.Println("3) All models: CPU vs GPU summary matrix (→ JSON)")
+	fmt.Println("4) Stress test: repeat one model's forward and check for drift")
+	fmt.Println("5) Partial offload: first K layers on GPU, rest on CPU vs all-CPU")
+	fmt.Println("6) Golden outputs: capture a reference or check a model against one")
+	fmt.Println("0) Back")
+	fmt.Print("Select: ")
+	modeRaw, _ := reader.ReadString('\n')
+	mode := strings.TrimSpace(modeRaw)
+	if mode == "0" {
+		return
+	}
+	if mode == "3" {
+		compareAllModels(modelDir)
+		return
+	}
+	if mode != "1" && mode != "2" && mode != "4" && mode != "5" && mode != "6" {
+		fmt.Println("❌ Invalid choice")
+		return
+	}
+
+	status := loadModelStatus()
 	fmt.Println("\nAvailable models:")
 	for i, m := range models {
-		fmt.Printf("%d) %s\n", i+1, m)
+		fmt.Printf("%d) %s%s %s\n", i+1, m, modelChoiceLabel(modelDir, m), modelStatusLabel(status, m))
 	}
 	fmt.Println("0) Back")
 
-	reader := bufio.NewReader(os.Stdin)
-	fmt.Print("Select model: ")
-	choiceRaw, _ := reader.ReadString('\n')
-	choice := strings.TrimSpace(choiceRaw)
+	pick := func(prompt string) (string, bool) {
+		fmt.Print(prompt)
+		choiceRaw, _ := reader.ReadString('\n')
+		choice := strings.TrimSpace(choiceRaw)
+		if choice == "0" {
+			return "", false
+		}
+		idx, err := strconv.Atoi(choice)
+		if err != nil || idx < 1 || idx > len(models) {
+			fmt.Println("❌ Invalid choice")
+			return "", false
+		}
+		return models[idx-1], true
+	}
 
-	if choice == "0" {
+	if mode == "2" {
+		a, ok := pick("Select model A: ")
+		if !ok {
+			return
+		}
+		b, ok := pick("Select model B: ")
+		if !ok {
+			return
+		}
+		fmt.Printf("\n▶ Comparing %s vs %s (CPU)\n", a, b)
+		compareTwoModels(filepath.Join(modelDir, a), filepath.Join(modelDir, b))
 		return
 	}
 
-	idx, err := strconv.Atoi(choice)
-	if err != nil || idx < 1 || idx > len(models) {
-		fmt.Println("❌ Invalid choice")
+	name, ok := pick("Select model: ")
+	if !ok {
 		return
 	}
+	if mode == "4" {
+		runStressPrompt(reader, filepath.Join(modelDir, name))
+		return
+	}
+	if mode == "5" {
+		runOffloadPrompt(reader, filepath.Join(modelDir, name))
+		return
+	}
+	if mode == "6" {
+		runGoldenPrompt(reader, filepath.Join(modelDir, name))
+		return
+	}
+	fmt.Print("Output format [table/json] (default table): ")
+	fmtRaw, _ := reader.ReadString('\n')
+	outFmt := strings.TrimSpace(strings.ToLower(fmtRaw))
+	if outFmt == "" {
+		outFmt = "table"
+	}
+	if outFmt != "table" && outFmt != "json" {
+		fmt.Println("❌ Invalid format")
+		return
+	}
+
+	digits, ok := promptDigits(reader)
+	if !ok {
+		return
+	}
+
+	modelPath := filepath.Join(modelDir, name)
+	if outFmt == "json" {
+		res, err := runCompareCPUvsGPU(modelPath, digits)
+		if err != nil {
+			fmt.Println("❌", err)
+			return
+		}
+		fmt.Println(res.ToJSON())
+		return
+	}
+
+	fmt.Print("Show full output vectors (all 10 classes)? [y/N]: ")
+	fullRaw, _ := reader.ReadString('\n')
+	full := strings.EqualFold(strings.TrimSpace(fullRaw), "y")
 
-	modelPath := filepath.Join(modelDir, models[idx-1])
-	fmt.Printf("\n▶ Running CPU vs GPU comparison for %s\n", models[idx-1])
-	compareSingleModel(modelPath)
+	fmt.Printf("\n▶ Running CPU vs GPU comparison for %s\n", name)
+	compareSingleModel(modelPath, full, digits)
 }
 
 // --- Bench menu (wired to sysbench.go) ---
@@ -199,7 +310,8 @@ func runBenchMenu() {
 	fmt.Println(" - ints    (int/uint types)")
 	fmt.Println(" - floats  (float32,float64)")
 	fmt.Println(" - custom  (comma list, e.g., int,int32,float32)")
-	fmt.Print("Choose filter [all/ints/floats/custom] (default all): ")
+	fmt.Println(" - scaling (float32 multi-threaded at 1,2,4…NumCPU threads → JSON)")
+	fmt.Print("Choose filter [all/ints/floats/custom/scaling] (default all): ")
 	fRaw, _ := reader.ReadString('\n')
 	fSel := strings.TrimSpace(strings.ToLower(fRaw))
 	var filter string
@@ -214,11 +326,34 @@ func runBenchMenu() {
 		fmt.Print("Enter comma-separated type list: ")
 		cRaw, _ := reader.ReadString('\n')
 		filter = strings.TrimSpace(cRaw)
+		if t := strings.ToLower(filter); t != "" && !strings.Contains(t, ",") {
+			fmt.Print("Single type: repeat runs to measure variance? runs (default 1 = normal bench): ")
+			rRaw, _ := reader.ReadString('\n')
+			if runs, err := strconv.Atoi(strings.TrimSpace(rRaw)); err == nil && runs > 1 {
+				runBenchVariance(reader, t, dur, runs)
+				return
+			}
+		}
+	case "scaling":
+		runBenchScaling(dur)
+		return
 	default:
 		fmt.Println("❌ Invalid filter choice")
 		return
 	}
 
+	// Run mode
+	fmt.Print("Run mode [sequential/isolated/adaptive] (default sequential): ")
+	modeRaw, _ := reader.ReadString('\n')
+	mode := strings.TrimSpace(strings.ToLower(modeRaw))
+	if mode == "" {
+		mode = BenchModeSequential
+	}
+	if mode != BenchModeSequential && mode != BenchModeIsolated && mode != BenchModeAdaptive {
+		fmt.Println("❌ Invalid run mode")
+		return
+	}
+
 	// Output format
 	fmt.Print("Output format [table/json] (default table): ")
 	fmtFmtRaw, _ := reader.ReadString('\n')
@@ -237,7 +372,9 @@ func runBenchMenu() {
 	outFile := strings.TrimSpace(outRaw)
 
 	// Run
-	info, err := CollectBenchmarks(dur, filter)
+	runCtx := captureRunContext()
+	runCtx.Operation = "bench"
+	info, err := CollectBenchmarksMode(dur, filter, mode)
 	if err != nil {
 		fmt.Println("❌ Benchmark error:", err)
 		return
@@ -252,13 +389,16 @@ func runBenchMenu() {
 				return
 			}
 			fmt.Printf("💾 JSON written → %s\n", outFile)
+			if err := writeRunContext(outFile, runCtx); err != nil {
+				fmt.Printf("⚠️  Could not write run context: %v\n", err)
+			}
 		}
 		return
 	}
 
 	// Pretty table
-	fmt.Printf("Numeric Microbench (dur=%.3gs, cpu=%d, filter=%s)\n",
-		info.DurationSec, info.NumCPU, info.Filter)
+	fmt.Printf("Numeric Microbench (dur=%.3gs, cpu=%d, filter=%s, mode=%s)\n",
+		info.DurationSec, info.NumCPU, info.Filter, info.RunMode)
 	fmt.Println("-------------------------------------------------------------")
 	fmt.Printf("%-10s | %-17s | %-17s\n", "Type", "Single-Threaded", "Multi-Threaded")
 	fmt.Println("-------------------------------------------------------------")
@@ -267,6 +407,17 @@ func runBenchMenu() {
 			r.Type, humanize(r.Single), humanize(r.Multi))
 	}
 	fmt.Println("-------------------------------------------------------------")
+	if len(info.Adaptive) > 0 {
+		fmt.Printf("Adaptive (target ±%.1f%%, cap %.3gs per measurement):\n", info.TargetRelErr*100, dur.Seconds())
+		for _, a := range info.Adaptive {
+			fmt.Printf("%-10s | single %.2fs ±%.2f%%%s | multi %.2fs ±%.2f%%%s\n", a.Type,
+				a.Single.Seconds, a.Single.RelErr*100, ternary(a.Single.Converged, "", " (cap)"),
+				a.Multi.Seconds, a.Multi.RelErr*100, ternary(a.Multi.Converged, "", " (cap)"))
+		}
+	}
+	if len(info.MissingTypes) > 0 {
+		fmt.Printf("⚠️  Requested but not benchmarked (unknown to paragon): %s\n", strings.Join(info.MissingTypes, ", "))
+	}
 
 	// Optional write JSON even in table mode
 	if outFile != "" {
@@ -276,7 +427,71 @@ func runBenchMenu() {
 			return
 		}
 		fmt.Printf("💾 JSON written → %s\n", outFile)
+		if err := writeRunContext(outFile, runCtx); err != nil {
+			fmt.Printf("⚠️  Could not write run context: %v\n", err)
+		}
+	}
+}
+
+// runBenchVariance benches one type repeatedly and prints the spread, so a
+// few-percent difference between machines can be told apart from noise.
+func runBenchVariance(reader *bufio.Reader, t string, dur time.Duration, runs int) {
+	fmt.Print("Threads [single/multi] (default multi): ")
+	thRaw, _ := reader.ReadString('\n')
+	multi := strings.TrimSpace(strings.ToLower(thRaw)) != "single"
+
+	fmt.Printf("▶ %s %s-threaded, %d × %v\n", t, ternary(multi, "multi", "single"), runs, dur)
+	var samples []float64
+	var err error
+	if multi {
+		samples, err = benchSingleType(t, dur, runs)
+	} else {
+		samples, err = benchSingleTypeMode(t, dur, runs, false)
+	}
+	if err != nil {
+		fmt.Println("❌ Benchmark error:", err)
+		return
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	mean, sd := meanStdDev(samples)
+	fmt.Println("-------------------------------------------------------------")
+	fmt.Printf("mean %s ops/s | sd %s | CV %.2f%% | min %s | median %s | max %s\n",
+		humanize(int(mean)), humanize(int(sd)), safeDiv(sd, mean)*100,
+		humanize(int(sorted[0])), humanize(int(percentile(sorted, 50))), humanize(int(sorted[len(sorted)-1])))
+	fmt.Println("-------------------------------------------------------------")
+	fmt.Println("ℹ️  Differences between machines smaller than ~2× CV are within run-to-run noise.")
+}
+
+// runBenchScaling runs the thread-scaling bench and writes the curve to
+// public/analysis/thread_scaling.json.
+func runBenchScaling(dur time.Duration) {
+	fmt.Printf("▶ float32 thread scaling, %v per level (1…%d threads)\n", dur, runtime.NumCPU())
+	curve, err := benchScaling("float32", dur)
+	if err != nil {
+		fmt.Println("❌ Benchmark error:", err)
+		return
+	}
+	// the first level whose efficiency drops below 70% is where extra cores
+	// stop paying for themselves (typically memory bandwidth)
+	for _, p := range curve.Points {
+		if p.Efficiency < 0.7 {
+			fmt.Printf("ℹ️  Scaling falls off at %d threads (efficiency %.0f%%).\n", p.Threads, p.Efficiency*100)
+			break
+		}
+	}
+
+	outDir, err := EnsurePublicDir("analysis")
+	if err != nil {
+		fmt.Println("❌", err)
+		return
 	}
+	jsonPath := filepath.Join(outDir, "thread_scaling.json")
+	if err := writeJSON(jsonPath, curve); err != nil {
+		fmt.Printf("❌ Failed to write %s: %v\n", jsonPath, err)
+		return
+	}
+	fmt.Printf("💾 Written → %s\n", jsonPath)
 }
 
 func humanize(n int) string {
@@ -316,13 +531,23 @@ func runWebMenu() {
 				port = v
 			}
 		}
+		bind := webBindDefault()
+		fmt.Printf("Bind address (0.0.0.0 = all interfaces, 127.0.0.1 = this machine only) [default %s]: ", bind)
+		if b, _ := reader.ReadString('\n'); strings.TrimSpace(b) != "" {
+			bind = strings.TrimSpace(b)
+		}
 		fmt.Print("Public dir [default public]: ")
 		d, _ := reader.ReadString('\n')
 		d = strings.TrimSpace(d)
 		if d == "" {
 			d = "public"
 		}
-		if err := StartWeb(port, d); err != nil {
+		comp := webCompressDefault()
+		fmt.Printf("Compress responses? [Y/n] (default %s): ", ternary(comp, "y", "n"))
+		if c, _ := reader.ReadString('\n'); strings.TrimSpace(c) != "" {
+			comp = !strings.EqualFold(strings.TrimSpace(c), "n")
+		}
+		if err := StartWeb(bind, port, d, comp); err != nil {
 			fmt.Println("❌", err)
 			return
 		}
@@ -339,7 +564,8 @@ func runWebMenu() {
 			return
 		}
 		fmt.Printf("✅ Running at http://%s\n", addr)
-		for _, u := range lanURLs(parsePort(addr)) {
+		host, _, _ := net.SplitHostPort(addr)
+		for _, u := range lanURLs(host, parsePort(addr)) {
 			fmt.Printf("   → %s\n", u)
 		}
 	default:
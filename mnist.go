@@ -1,43 +1,289 @@
 package main
 
 import (
+	"context"
 	"encoding/binary"
+	"errors"
+	"flag"
 	"fmt"
 	"image"
 	"image/color"
 	"image/png"
+	"io/fs"
+	"math"
+	"math/rand"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"path/filepath"
 )
 
-// Loads both training and test images, returns as one dataset
-func loadMNISTData(dir string) ([][][]float64, [][][]float64, error) {
-	images := make([][][]float64, 0)
-	labels := make([][][]float64, 0)
+// Package-level MNIST cache so chained menu actions don't re-parse ~55MB of
+// IDX files every time. Use getMNIST() instead of loadMNISTData directly.
+var (
+	mnistMu     sync.Mutex
+	mnistOnce   sync.Once
+	mnistImages [][][]float64
+	mnistLabels [][][]float64
+	mnistErr    error
+	mnistIndex  map[*float64]int // first pixel of each sample → dataset index
 
-	for _, set := range []string{"train", "t10k"} {
-		imgPath := filepath.Join(dir, set+"-images-idx3-ubyte")
-		lblPath := filepath.Join(dir, set+"-labels-idx1-ubyte")
+	mnistTrainCount int // samples at the front of mnistImages that come from the train set
 
-		imgs, err := loadMNISTImages(imgPath)
-		if err != nil {
-			return nil, nil, err
+	// Dev knob: only keep the first N samples so train/evaluate finish in seconds.
+	flagMaxSamples = flag.Int("max-samples", 0, "Load only the first N MNIST samples (0 = all; not comparable to full runs)")
+)
+
+// maxSamples returns the dataset cap (0 = no cap). PARAGON_MAX_SAMPLES wins
+// over --max-samples, mirroring how BaseDir treats PARAGON_DATA_DIR.
+//
+// NOTE: a capped dataset takes the first N samples in file order (train set
+// first), so scores/timings from capped runs are smoke-test numbers only and
+// must not be compared against full 70k runs.
+func maxSamples() int {
+	if v := strings.TrimSpace(os.Getenv("PARAGON_MAX_SAMPLES")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
 		}
+		return 0
+	}
+	if flag.Parsed() && *flagMaxSamples > 0 {
+		return *flagMaxSamples
+	}
+	return 0
+}
 
-		lbls, err := loadMNISTLabels(lblPath)
+// getMNIST returns the combined train+t10k dataset from public/mnist, loading
+// it on first use and serving the cached copy afterwards. Failed loads are not
+// cached, so a later call retries (e.g. after option 2 downloads the files).
+// Callers must treat the returned slices as read-only.
+func getMNIST() ([][][]float64, [][][]float64, error) {
+	return getMNISTCtx(context.Background())
+}
+
+// getMNISTCtx is getMNIST with a cancellable first load. A cancelled load is
+// not cached, so the next caller starts over.
+func getMNISTCtx(ctx context.Context) ([][][]float64, [][][]float64, error) {
+	mnistMu.Lock()
+	defer mnistMu.Unlock()
+
+	mnistOnce.Do(func() {
+		start := time.Now()
+		mnistImages, mnistLabels, mnistTrainCount, mnistErr = loadMNISTCombinedCtx(ctx, MustPublicPath("mnist"))
+		recordTiming("load", time.Since(start))
+		if mnistErr == nil {
+			mnistIndex = make(map[*float64]int, len(mnistImages))
+			for i, img := range mnistImages {
+				if len(img) > 0 && len(img[0]) > 0 {
+					mnistIndex[&img[0][0]] = i
+				}
+			}
+		}
+	})
+	images, labels, err := mnistImages, mnistLabels, mnistErr
+	if err != nil {
+		mnistOnce = sync.Once{}
+		mnistImages, mnistLabels, mnistErr, mnistIndex, mnistTrainCount = nil, nil, nil, nil, 0
+	}
+	return images, labels, err
+}
+
+// mnistIndexOf maps a sample handed out by getMNIST (possibly reshuffled by
+// splitMNIST) back to its index in the combined dataset — the same
+// index exportMNISTAsPNGs uses in file names. Returns -1 if unknown.
+func mnistIndexOf(img [][]float64) int {
+	mnistMu.Lock()
+	defer mnistMu.Unlock()
+	if len(img) == 0 || len(img[0]) == 0 || mnistIndex == nil {
+		return -1
+	}
+	if i, ok := mnistIndex[&img[0][0]]; ok {
+		return i
+	}
+	return -1
+}
+
+// mnistPNGPath returns the PNG exported by option 3 for dataset index idx,
+// or "" if it hasn't been exported.
+func mnistPNGPath(idx, label int) string {
+	if idx < 0 {
+		return ""
+	}
+	p, err := PublicPath("mnist_png", "all", fmt.Sprintf("%d", label), fmt.Sprintf("img_%05d.png", idx))
+	if err != nil {
+		return ""
+	}
+	if _, err := os.Stat(p); err != nil {
+		return ""
+	}
+	return p
+}
+
+// invalidateMNIST drops the cached dataset so the next getMNIST() re-reads
+// the files from disk (call after the IDX files change underneath us).
+func invalidateMNIST() {
+	mnistMu.Lock()
+	defer mnistMu.Unlock()
+	mnistOnce = sync.Once{}
+	mnistImages, mnistLabels, mnistErr, mnistIndex, mnistTrainCount = nil, nil, nil, nil, 0
+}
+
+// Loads both training and test images, returns as one dataset.
+// Honors maxSamples(): once the cap is reached the remaining set is skipped.
+func loadMNISTData(dir string) ([][][]float64, [][][]float64, error) {
+	return loadMNISTDataCtx(context.Background(), dir)
+}
+
+// loadMNISTDataCtx is loadMNISTData that gives up when ctx is done. ctx is
+// checked between samples, so a stalled read is abandoned at the next sample
+// boundary rather than mid-read.
+func loadMNISTDataCtx(ctx context.Context, dir string) ([][][]float64, [][][]float64, error) {
+	images, labels, _, err := loadMNISTCombinedCtx(ctx, dir)
+	return images, labels, err
+}
+
+// loadMNISTCombinedCtx does the work for loadMNISTDataCtx and also reports
+// how many of the returned samples came from the official train set (the
+// rest are t10k), so the canonical 60k/10k split can be recovered.
+func loadMNISTCombinedCtx(ctx context.Context, dir string) (images, labels [][][]float64, nTrain int, err error) {
+	images = make([][][]float64, 0)
+	labels = make([][][]float64, 0)
+	limit := maxSamples()
+
+	for _, set := range []string{"train", "t10k"} {
+		imgs, lbls, err := loadMNISTSetCtx(ctx, dir, set)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, 0, err
 		}
 
+		if w, have := oneHotWidth(lbls), oneHotWidth(labels); w != have && have > 0 {
+			widenOneHot(labels, w)
+			widenOneHot(lbls, have)
+		}
 		images = append(images, imgs...)
 		labels = append(labels, lbls...)
+		if set == "train" {
+			nTrain = len(images)
+		}
+
+		if limit > 0 && len(images) >= limit {
+			images, labels = images[:limit], labels[:limit]
+			nTrain = min(nTrain, limit)
+			break
+		}
+	}
+
+	return images, labels, nTrain, nil
+}
+
+// loadMNISTSet loads one official MNIST set: "train" (60k) or "t10k" (10k).
+func loadMNISTSet(dir, set string) ([][][]float64, [][][]float64, error) {
+	return loadMNISTSetCtx(context.Background(), dir, set)
+}
+
+func loadMNISTSetCtx(ctx context.Context, dir, set string) ([][][]float64, [][][]float64, error) {
+	if set != "train" && set != "t10k" {
+		return nil, nil, fmt.Errorf("unknown MNIST set %q (want train or t10k)", set)
+	}
+	imgs, err := loadMNISTImagesCtx(ctx, filepath.Join(dir, set+"-images-idx3-ubyte"))
+	if err != nil {
+		return nil, nil, wrapMNISTErr(err)
+	}
+	lbls, err := loadMNISTLabelsCtx(ctx, filepath.Join(dir, set+"-labels-idx1-ubyte"), numClasses())
+	if err != nil {
+		return nil, nil, wrapMNISTErr(err)
+	}
+	return imgs, lbls, nil
+}
+
+// --official-split makes train/evaluate use MNIST's own 60k train / 10k t10k
+// split instead of the seeded 80/20 shuffle over the combined pool, which
+// lets the official test set leak into training.
+var flagOfficialSplit = flag.Bool("official-split", false, "Train/evaluate on the official MNIST 60k train / 10k t10k split instead of a seeded 80/20 over both")
+
+func officialSplit() bool {
+	return flag.Parsed() && *flagOfficialSplit
+}
+
+// splitSeed fixes the 80/20 shuffle, so every run — training, evaluation,
+// quick scores — puts the same samples on the test side.
+const splitSeed int64 = 80_20
+
+// splitLabel names the split splitMNIST uses, for printing and result files.
+func splitLabel() string {
+	return ternary(officialSplit(), "official", "seeded_80_20")
+}
+
+// splitMNIST splits the dataset returned by getMNIST into train and test
+// sets: the official train/t10k boundary with --official-split, otherwise an
+// 80/20 shuffle seeded with splitSeed. If a --max-samples cap left no t10k
+// samples, it falls back to 80/20 with a warning.
+func splitMNIST(images, labels [][][]float64) (trainIn, trainTarg, testIn, testTarg [][][]float64) {
+	if officialSplit() {
+		mnistMu.Lock()
+		n := mnistTrainCount
+		mnistMu.Unlock()
+		if n > 0 && n < len(images) {
+			return images[:n], labels[:n], images[n:], labels[n:]
+		}
+		fmt.Println("⚠️  Official split unavailable (no t10k samples loaded — check --max-samples); using the seeded 80/20.")
 	}
+	perm := rand.New(rand.NewSource(splitSeed)).Perm(len(images))
+	cut := int(0.8 * float64(len(images)))
+	for i, j := range perm {
+		if i < cut {
+			trainIn, trainTarg = append(trainIn, images[j]), append(trainTarg, labels[j])
+		} else {
+			testIn, testTarg = append(testIn, images[j]), append(testTarg, labels[j])
+		}
+	}
+	return trainIn, trainTarg, testIn, testTarg
+}
 
-	return images, labels, nil
+// missingMNISTFiles lists which of the four IDX files are absent from dir.
+func missingMNISTFiles(dir string) []string {
+	var missing []string
+	for _, fn := range mnistFiles {
+		if _, err := os.Stat(filepath.Join(dir, fn)); err != nil {
+			missing = append(missing, fn)
+		}
+	}
+	return missing
+}
+
+// ensureMNISTOrGuide checks that the MNIST IDX files are in public/mnist and,
+// if not, explains how to get them instead of letting the caller fail with a
+// raw file error. MNIST-dependent menu actions call it first.
+func ensureMNISTOrGuide() error {
+	dir := MustPublicPath("mnist")
+	missing := missingMNISTFiles(dir)
+	if len(missing) == 0 {
+		return nil
+	}
+	fmt.Printf("📭 MNIST dataset not found in %s (missing: %s)\n", dir, strings.Join(missing, ", "))
+	fmt.Println("   This option needs it. To get it, either:")
+	fmt.Println("   • choose option 2 (PILOT MNIST experiment) — it downloads the dataset, or")
+	fmt.Println("   • run telemetry (option 12) against a host — it pulls the files from <host>/mnist/, or")
+	fmt.Printf("   • copy the four IDX files (%s) into %s yourself.\n", strings.Join(mnistFiles, ", "), dir)
+	return fmt.Errorf("%w in %s", ErrMNISTMissing, dir)
+}
+
+// wrapMNISTErr tags "file not there" failures with ErrMNISTMissing.
+func wrapMNISTErr(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %w", ErrMNISTMissing, err)
+	}
+	return err
 }
 
 func loadMNISTImages(path string) ([][][]float64, error) {
+	return loadMNISTImagesCtx(context.Background(), path)
+}
+
+func loadMNISTImagesCtx(ctx context.Context, path string) ([][][]float64, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -55,6 +301,9 @@ func loadMNISTImages(path string) ([][][]float64, error) {
 	images := make([][][]float64, num)
 	buf := make([]byte, rows*cols)
 	for i := 0; i < num; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("%s: stopped after %d/%d images: %w", filepath.Base(path), i, num, err)
+		}
 		if _, err := f.Read(buf); err != nil {
 			return nil, err
 		}
@@ -71,6 +320,23 @@ func loadMNISTImages(path string) ([][][]float64, error) {
 }
 
 func loadMNISTLabels(path string) ([][][]float64, error) {
+	return loadMNISTLabelsCtx(context.Background(), path, numClasses())
+}
+
+// --num-classes sets the one-hot width for label files. 0 derives it from the
+// labels (highest label + 1), which is 10 for MNIST.
+var flagNumClasses = flag.Int("num-classes", 0, "Number of label classes (one-hot width); 0 = derive from the dataset")
+
+func numClasses() int {
+	if flag.Parsed() && *flagNumClasses > 0 {
+		return *flagNumClasses
+	}
+	return 0
+}
+
+// loadMNISTLabelsCtx reads an IDX label file as one-hot targets of width
+// numClasses, or of width highest-label+1 when numClasses is 0.
+func loadMNISTLabelsCtx(ctx context.Context, path string, numClasses int) ([][][]float64, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -83,24 +349,64 @@ func loadMNISTLabels(path string) ([][][]float64, error) {
 	}
 	num := int(binary.BigEndian.Uint32(header[4:8]))
 
-	labels := make([][][]float64, num)
+	raw := make([]int, num)
+	highest := 0
 	for i := 0; i < num; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("%s: stopped after %d/%d labels: %w", filepath.Base(path), i, num, err)
+		}
 		var b [1]byte
 		if _, err := f.Read(b[:]); err != nil {
 			return nil, err
 		}
-		labels[i] = labelToOneHot(int(b[0]))
+		raw[i] = int(b[0])
+		highest = max(highest, raw[i])
+	}
+
+	if numClasses <= 0 {
+		numClasses = highest + 1
+	} else if highest >= numClasses {
+		return nil, fmt.Errorf("%s: label %d does not fit %d classes (--num-classes)", filepath.Base(path), highest, numClasses)
+	}
+	labels := make([][][]float64, num)
+	for i, l := range raw {
+		labels[i] = labelToOneHotN(l, numClasses)
 	}
 	return labels, nil
 }
 
-func labelToOneHot(label int) [][]float64 {
+// labelToOneHotN encodes label as a 1×numClasses one-hot target. A label
+// outside [0, numClasses) gives an all-zero row.
+func labelToOneHotN(label, numClasses int) [][]float64 {
 	t := make([][]float64, 1)
-	t[0] = make([]float64, 10)
-	t[0][label] = 1.0
+	t[0] = make([]float64, numClasses)
+	if label >= 0 && label < numClasses {
+		t[0][label] = 1.0
+	}
 	return t
 }
 
+// widenOneHot pads one-hot rows narrower than n with zeros, so two label
+// sets whose widths were derived separately (e.g. a test set missing the
+// top class) line up.
+func widenOneHot(labels [][][]float64, n int) {
+	for i, t := range labels {
+		if len(t[0]) < n {
+			row := make([]float64, n)
+			copy(row, t[0])
+			labels[i] = [][]float64{row}
+		}
+	}
+}
+
+// oneHotWidth is the width of the first target in labels (0 if empty).
+func oneHotWidth(labels [][][]float64) int {
+	if len(labels) == 0 {
+		return 0
+	}
+	return len(labels[0][0])
+}
+
 // Export all MNIST images as PNGs into public/mnist_png/[train|t10k]
 func exportMNISTAsPNGs(images [][][]float64, labels [][][]float64, setName string) error {
 	// Use MustPublicPath for cross-platform compatibility
@@ -166,6 +472,54 @@ func exportMNISTAsPNGs(images [][][]float64, labels [][][]float64, setName strin
 	return nil
 }
 
+// Augmentation limits: small enough that a digit stays the same digit.
+const (
+	augmentMaxShift  = 2    // pixels, each axis
+	augmentMaxRotate = 10.0 // degrees, either direction
+)
+
+// augmentMNIST returns a randomly shifted/rotated copy of img (the source is
+// never modified). Sampling is nearest-neighbour around the image centre;
+// pixels that fall outside the frame become 0 (background).
+func augmentMNIST(img [][]float64, seed int64) [][]float64 {
+	rows := len(img)
+	if rows == 0 {
+		return img
+	}
+	cols := len(img[0])
+	rng := rand.New(rand.NewSource(seed))
+
+	dx := float64(rng.Intn(2*augmentMaxShift+1) - augmentMaxShift)
+	dy := float64(rng.Intn(2*augmentMaxShift+1) - augmentMaxShift)
+	theta := (rng.Float64()*2 - 1) * augmentMaxRotate * math.Pi / 180
+	sin, cos := math.Sincos(theta)
+	cx, cy := float64(cols-1)/2, float64(rows-1)/2
+
+	out := make([][]float64, rows)
+	for r := 0; r < rows; r++ {
+		out[r] = make([]float64, cols)
+		for c := 0; c < cols; c++ {
+			// inverse map: undo the shift, then rotate back by -theta
+			x, y := float64(c)-dx-cx, float64(r)-dy-cy
+			sc := int(math.Round(cos*x + sin*y + cx))
+			sr := int(math.Round(-sin*x + cos*y + cy))
+			if sr >= 0 && sr < rows && sc >= 0 && sc < cols {
+				out[r][c] = img[sr][sc]
+			}
+		}
+	}
+	return out
+}
+
+// augmentSet applies augmentMNIST to every input, deriving one seed per sample.
+func augmentSet(inputs [][][]float64, seed int64) [][][]float64 {
+	out := make([][][]float64, len(inputs))
+	for i, img := range inputs {
+		out[i] = augmentMNIST(img, seed+int64(i))
+	}
+	return out
+}
+
 func flattenMNIST64(img [][]float64) [][]float64 {
 	rows, cols := len(img), len(img[0])
 	out := make([][]float64, 1)
@@ -178,7 +532,14 @@ func flattenMNIST64(img [][]float64) [][]float64 {
 	return out
 }
 
+// argmax64 returns the index of the largest value, or -1 (with a warning)
+// for an empty vector — e.g. a GPU forward that returned nothing — so one bad
+// output is reported as "no prediction" instead of crashing the whole run.
 func argmax64(v []float64) int {
+	if len(v) == 0 {
+		fmt.Println("⚠️  Empty model output; prediction set to -1")
+		return -1
+	}
 	best, idx := v[0], 0
 	for i := 1; i < len(v); i++ {
 		if v[i] > best {
@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+// TestSplitMNISTIsStable checks the default 80/20 split puts the same
+// samples on the test side every time, none of them on the train side.
+func TestSplitMNISTIsStable(t *testing.T) {
+	images := make([][][]float64, 50)
+	labels := make([][][]float64, 50)
+	for i := range images {
+		images[i] = [][]float64{{float64(i)}}
+		labels[i] = [][]float64{{float64(i % 10)}}
+	}
+	trainA, _, testA, _ := splitMNIST(images, labels)
+	_, _, testB, _ := splitMNIST(images, labels)
+	if len(trainA) != 40 || len(testA) != 10 {
+		t.Fatalf("split %d/%d, want 40/10", len(trainA), len(testA))
+	}
+	inTrain := map[float64]bool{}
+	for _, img := range trainA {
+		inTrain[img[0][0]] = true
+	}
+	for i := range testA {
+		if testA[i][0][0] != testB[i][0][0] {
+			t.Fatalf("test side differs between calls at %d", i)
+		}
+		if inTrain[testA[i][0][0]] {
+			t.Fatalf("sample %v is on both sides", testA[i][0][0])
+		}
+	}
+}
@@ -1,11 +1,17 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/openfluke/paragon/v3"
@@ -25,7 +31,7 @@ func createModelZoo() {
 	start := time.Now()
 
 	// 1) Ensure output dir
-	modelDir := MustPublicPath("models")
+	modelDir := ModelsDir()
 
 	fmt.Printf("📂 Model directory: %s\n", modelDir)
 
@@ -48,102 +54,644 @@ func createModelZoo() {
 		{ID: "XL2", Layers: []string{"784", "2048", "2048", "2048", "2048", "10"}},
 	}
 
-	// helper to build Paragon shapes from Layers
-	toParagonShapes := func(s ModelSpec) []struct{ Width, Height int } {
-		// Represent as [in] [hidden...] [out], using Height as 1 except input 28x28.
-		// Paragon’s example you showed used {28,28}, {N,N?}, {10,1}. We’ll keep height=1 for dense.
-		shapes := make([]struct{ Width, Height int }, 0, len(s.Layers))
-		for i, l := range s.Layers {
-			switch i {
-			case 0:
-				// input = 28x28
-				shapes = append(shapes, struct{ Width, Height int }{28, 28})
-			case len(s.Layers) - 1:
-				// output = 10x1
-				shapes = append(shapes, struct{ Width, Height int }{10, 1})
-			default:
-				// hidden: Nx1
-				var w int
-				fmt.Sscanf(l, "%d", &w)
-				shapes = append(shapes, struct{ Width, Height int }{w, 1})
+	// Specs are independent (NewNetwork + saveModel per model), so build them
+	// on a worker pool. Each worker fills its spec's slot, which keeps the
+	// manifest in spec order however the workers finish.
+	slots := make([]*ModelSpec, len(specs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < zooWorkers(len(specs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				slots[i] = buildZooModel(modelDir, specs[i])
 			}
+		}()
+	}
+	for i := range specs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	manifest := make([]ModelSpec, 0, len(specs))
+	for _, spec := range slots {
+		if spec != nil {
+			manifest = append(manifest, *spec)
 		}
-		return shapes
 	}
 
-	// same activations for all: linear → relu...(for hidden)... → softmax
-	buildActivs := func(s ModelSpec) []string {
-		acts := make([]string, 0, len(s.Layers))
-		for i := range s.Layers {
-			if i == 0 {
-				acts = append(acts, "linear") // input pass-through
-			} else if i == len(s.Layers)-1 {
-				acts = append(acts, "softmax")
-			} else {
-				acts = append(acts, "relu")
+	// 3) Merge into the existing manifest (keeps models added by other means)
+	manPath := filepath.Join(modelDir, "manifest.json")
+	existing, err := readManifest(manPath)
+	if err != nil {
+		bak := manPath + ".bak"
+		fmt.Printf("⚠️  existing manifest unreadable (%v); moving it to %s\n", err, bak)
+		_ = os.Rename(manPath, bak)
+		existing = nil
+	}
+	merged := mergeManifest(existing, manifest)
+	if err := writeJSON(manPath, merged); err != nil {
+		fmt.Printf("❌ manifest write failed: %v\n", err)
+	} else {
+		fmt.Printf("📜 manifest written → %s (%d entries, %d from this run)\n", manPath, len(merged), len(manifest))
+	}
+
+	fmt.Printf("✅ Model zoo ready in %v\n", time.Since(start))
+}
+
+// --compact-models saves models without indentation. Paragon's SaveJSON
+// indents every neuron, which roughly doubles the XL files; compact files load
+// the same and download faster in the telemetry path. Small artifacts
+// (manifest, reports) stay indented either way.
+var flagCompactModels = flag.Bool("compact-models", false, "Save models as compact (unindented) JSON — much smaller XL files")
+
+func compactModels() bool {
+	return flag.Parsed() && *flagCompactModels
+}
+
+// saveModel writes nn to path, compact with --compact-models and via
+// paragon's indented SaveJSON otherwise.
+func saveModel(nn *paragon.Network[float32], path string) error {
+	if !compactModels() {
+		return nn.SaveJSON(path)
+	}
+	b, err := nn.MarshalJSONModel()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// --zoo-workers caps how many zoo models are built at once. The XL models
+// hold their whole JSON encoding in memory while saving, so the default stays
+// at 4 even on machines with more cores.
+var flagZooWorkers = flag.Int("zoo-workers", 0, "Models to build in parallel during zoo creation (0 = min(NumCPU, 4))")
+
+func zooWorkers(specs int) int {
+	n := min(runtime.NumCPU(), 4)
+	if flag.Parsed() && *flagZooWorkers > 0 {
+		n = *flagZooWorkers
+	}
+	return max(1, min(n, specs))
+}
+
+// buildZooModel builds and saves one zoo spec, or records the existing file
+// when it is already on disk. It returns the manifest entry, or nil if the
+// model could not be built.
+func buildZooModel(modelDir string, base ModelSpec) *ModelSpec {
+	spec := base
+	spec.Activs = specActivations(spec)
+	spec.Trainable = allTrainable(len(spec.Layers))
+	spec.Filename = fmt.Sprintf("mnist_%s.json", spec.ID)
+	outPath := filepath.Join(modelDir, spec.Filename)
+
+	// Skip if exists
+	if fi, err := os.Stat(outPath); err == nil {
+		spec.Bytes = fi.Size()
+		fmt.Printf("⚠️  %s already exists (%s), skipping\n", spec.ID, outPath)
+		return &spec
+	}
+
+	// Build & save
+	startInit := time.Now()
+	nn, err := buildSpecNetwork(spec)
+	if err != nil {
+		fmt.Printf("❌ %s init failed: %v\n", spec.ID, err)
+		return nil
+	}
+
+	fmt.Printf("⏱ %s init: %v\n", spec.ID, time.Since(startInit))
+
+	startSave := time.Now()
+	if err := saveModel(nn, outPath); err != nil {
+		fmt.Printf("❌ %s save failed: %v\n", spec.ID, err)
+		return nil
+	}
+	saveDur := time.Since(startSave)
+	recordTiming("save", saveDur)
+
+	fi, _ := os.Stat(outPath)
+	spec.Bytes = fi.Size()
+	spec.Params = countParams(nn)
+	fmt.Printf("💾 %s saved → %s (%d bytes) in %v\n", spec.ID, outPath, spec.Bytes, saveDur)
+	return &spec
+}
+
+// listModels returns every model JSON under dir (recursively) as a sorted list
+// of slash-separated paths relative to dir, e.g. "mnist_S1.json" or
+// "exp1/mnist_S1.json". manifest.json/status.json and hidden directories are skipped.
+func listModels(dir string) ([]string, error) {
+	var models []string
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
 			}
+			return nil
+		}
+		if !strings.HasSuffix(d.Name(), ".json") || d.Name() == "manifest.json" || d.Name() == "status.json" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return nil
+		}
+		models = append(models, filepath.ToSlash(rel))
+		return nil
+	})
+	sort.Strings(models)
+	return models, err
+}
+
+// safeModelRelPath reports whether rel (from a manifest) stays inside the
+// models directory once joined — no absolute paths, no "..".
+func safeModelRelPath(rel string) bool {
+	if rel == "" || strings.HasPrefix(rel, "/") || filepath.IsAbs(rel) {
+		return false
+	}
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if part == ".." {
+			return false
 		}
-		return acts
 	}
+	return true
+}
 
-	buildTrainable := func(n int) []bool {
-		tb := make([]bool, n)
-		for i := range tb {
-			tb[i] = true
+// countParams returns the number of trainable scalars (weights + biases).
+func countParams[T paragon.Numeric](nn *paragon.Network[T]) int64 {
+	var n int64
+	for li := 1; li < len(nn.Layers); li++ {
+		for _, row := range nn.Layers[li].Neurons {
+			for _, neuron := range row {
+				if neuron == nil {
+					continue
+				}
+				n += int64(len(neuron.Inputs)) + 1
+			}
 		}
-		return tb
 	}
+	return n
+}
 
-	manifest := make([]ModelSpec, 0, len(specs))
+// describeModel returns a one-line summary of the model at path, e.g.
+// "784 → 64 → 10 (softmax), 50.9K params". The manifest spec is used when the
+// models dir has one for it, so menus don't parse multi-GB JSON just to label
+// a choice; otherwise the model is loaded once and the summary cached by size
+// and mtime.
+func describeModel(path string) (string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if spec, ok := manifestSpecFor(path); ok {
+		shapes := specShapes(spec)
+		acts := spec.Activs
+		if len(acts) != len(shapes) {
+			acts = specActivations(spec)
+		}
+		params := spec.Params
+		if params == 0 {
+			for i := 1; i < len(shapes); i++ {
+				params += int64(shapes[i].Width*shapes[i].Height) * int64(shapes[i-1].Width*shapes[i-1].Height+1)
+			}
+		}
+		return formatTopology(shapes, acts, params), nil
+	}
 
-	for _, base := range specs {
-		spec := base
-		spec.Activs = buildActivs(spec)
-		spec.Trainable = buildTrainable(len(spec.Layers))
-		spec.Filename = fmt.Sprintf("mnist_%s.json", spec.ID)
-		outPath := filepath.Join(modelDir, spec.Filename)
-
-		// Skip if exists
-		if _, err := os.Stat(outPath); err == nil {
-			fi, _ := os.Stat(outPath)
-			spec.Bytes = fi.Size()
-			manifest = append(manifest, spec)
-			fmt.Printf("⚠️  %s already exists (%s), skipping\n", spec.ID, outPath)
+	describeMu.Lock()
+	c, ok := describeCache[path]
+	describeMu.Unlock()
+	if ok && c.size == fi.Size() && c.modTime.Equal(fi.ModTime()) {
+		return c.desc, nil
+	}
+	nn, err := loadFloat32Model(path)
+	if err != nil {
+		return "", err
+	}
+	shapes := make([]struct{ Width, Height int }, len(nn.Layers))
+	for i, L := range nn.Layers {
+		shapes[i] = struct{ Width, Height int }{L.Width, L.Height}
+	}
+	desc := formatTopology(shapes, layerActivations(nn, nil), countParams(nn))
+	describeMu.Lock()
+	describeCache[path] = describedModel{size: fi.Size(), modTime: fi.ModTime(), desc: desc}
+	describeMu.Unlock()
+	return desc, nil
+}
+
+var (
+	describeMu    sync.Mutex
+	describeCache = map[string]describedModel{}
+)
+
+type describedModel struct {
+	size    int64
+	modTime time.Time
+	desc    string
+}
+
+// manifestSpecFor finds path's spec in the manifest of the models dir.
+func manifestSpecFor(path string) (ModelSpec, bool) {
+	modelDir := ModelsDir()
+	rel, err := filepath.Rel(modelDir, path)
+	if err != nil || !safeModelRelPath(rel) {
+		return ModelSpec{}, false
+	}
+	specs, err := readManifest(filepath.Join(modelDir, "manifest.json"))
+	if err != nil {
+		return ModelSpec{}, false
+	}
+	for _, s := range specs {
+		if filepath.ToSlash(s.Filename) == filepath.ToSlash(rel) && len(s.Layers) >= 2 {
+			return s, true
+		}
+	}
+	return ModelSpec{}, false
+}
+
+// formatTopology renders layer sizes with the output activation, plus the
+// hidden activation when it isn't the usual relu.
+func formatTopology(shapes []struct{ Width, Height int }, acts []string, params int64) string {
+	sizes := make([]string, len(shapes))
+	for i, s := range shapes {
+		sizes[i] = strconv.Itoa(s.Width * s.Height)
+	}
+	act := ""
+	if n := len(acts); n > 0 {
+		act = acts[n-1]
+		if n > 2 && acts[n-2] != "relu" {
+			act = acts[n-2] + " → " + act
+		}
+	}
+	return fmt.Sprintf("%s (%s), %s params", strings.Join(sizes, " → "), act, humanCount(params))
+}
+
+// humanCount formats n as 512, 50.9K or 12.6M.
+func humanCount(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fK", float64(n)/1e3)
+	default:
+		return strconv.FormatInt(n, 10)
+	}
+}
+
+// modelChoiceLabel is describeModel for a menu line: " — <summary>", or ""
+// if the model can't be described.
+func modelChoiceLabel(modelDir, name string) string {
+	desc, err := describeModel(filepath.Join(modelDir, filepath.FromSlash(name)))
+	if err != nil {
+		return ""
+	}
+	return " — " + desc
+}
+
+// specShapes builds Paragon shapes from spec.Layers.
+// Represent as [in] [hidden...] [out], using Height as 1 except input 28x28.
+// Paragon’s example you showed used {28,28}, {N,N?}, {10,1}. We’ll keep height=1 for dense.
+func specShapes(s ModelSpec) []struct{ Width, Height int } {
+	shapes := make([]struct{ Width, Height int }, 0, len(s.Layers))
+	for i, l := range s.Layers {
+		switch i {
+		case 0:
+			// input = 28x28
+			shapes = append(shapes, struct{ Width, Height int }{28, 28})
+		case len(s.Layers) - 1:
+			// output = 10x1
+			shapes = append(shapes, struct{ Width, Height int }{10, 1})
+		default:
+			// hidden: Nx1
+			var w int
+			fmt.Sscanf(l, "%d", &w)
+			shapes = append(shapes, struct{ Width, Height int }{w, 1})
+		}
+	}
+	return shapes
+}
+
+// specActivations: same activations for all: linear → relu...(for hidden)... → softmax
+func specActivations(s ModelSpec) []string {
+	acts := make([]string, 0, len(s.Layers))
+	for i := range s.Layers {
+		if i == 0 {
+			acts = append(acts, "linear") // input pass-through
+		} else if i == len(s.Layers)-1 {
+			acts = append(acts, "softmax")
+		} else {
+			acts = append(acts, "relu")
+		}
+	}
+	return acts
+}
+
+func allTrainable(n int) []bool {
+	tb := make([]bool, n)
+	for i := range tb {
+		tb[i] = true
+	}
+	return tb
+}
+
+// buildSpecNetwork creates a freshly-initialized float32 network for spec.
+// Activations/trainable flags recorded in the spec win over the defaults.
+func buildSpecNetwork(spec ModelSpec) (*paragon.Network[float32], error) {
+	acts := spec.Activs
+	if len(acts) != len(spec.Layers) {
+		acts = specActivations(spec)
+	}
+	trains := spec.Trainable
+	if len(trains) != len(spec.Layers) {
+		trains = allTrainable(len(spec.Layers))
+	}
+	return paragon.NewNetwork[float32](specShapes(spec), acts, trains)
+}
+
+var flagActivations = flag.String("activations", "", "Comma-separated per-layer activations (e.g. linear,relu,softmax) to use when rebuilding loaded models; empty entries keep the detected one")
+
+// activationOverride parses --activations; nil when unset.
+func activationOverride() []string {
+	if !flag.Parsed() || strings.TrimSpace(*flagActivations) == "" {
+		return nil
+	}
+	parts := strings.Split(*flagActivations, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+// layerTrainFlags recovers the per-layer flags nn was built with — the
+// ModelSpec "trainable" slice, which paragon's NewNetwork takes as each
+// layer's connectivity (true: fully connected to the previous layer, false:
+// local 5×5 window). The saved JSON has no field for them, so they are read
+// back from the connections: a layer is fully connected when its first
+// neuron has one input per neuron of the previous layer, the same test
+// paragon uses.
+func layerTrainFlags(nn *paragon.Network[float32]) []bool {
+	flags := make([]bool, len(nn.Layers))
+	if len(flags) > 0 {
+		flags[0] = true // the input layer has no inputs; NewNetwork ignores it
+	}
+	for i := 1; i < len(nn.Layers); i++ {
+		prev, L := nn.Layers[i-1], nn.Layers[i]
+		if len(L.Neurons) == 0 || len(L.Neurons[0]) == 0 || L.Neurons[0][0] == nil {
+			flags[i] = true
 			continue
 		}
+		flags[i] = len(L.Neurons[0][0].Inputs) == prev.Width*prev.Height
+	}
+	return flags
+}
 
-		// Build & save
-		startInit := time.Now()
-		nn, err := paragon.NewNetwork[float32](toParagonShapes(spec), spec.Activs, spec.Trainable)
-		if err != nil {
-			fmt.Printf("❌ %s init failed: %v\n", spec.ID, err)
+// warnTrainFlagMismatch reports when the trainable flags recovered from a
+// saved model differ from the ones its manifest spec was built with, e.g.
+// a frozen/local layer swapped for a dense one by a later retrain.
+func warnTrainFlagMismatch(modelPath string, got []bool) {
+	spec, ok := manifestSpecFor(modelPath)
+	if !ok || len(spec.Trainable) == 0 {
+		return
+	}
+	if len(spec.Trainable) != len(got) {
+		fmt.Printf("⚠️  %s: manifest lists %d layers, the file has %d\n", filepath.Base(modelPath), len(spec.Trainable), len(got))
+		return
+	}
+	for i := 1; i < len(got); i++ {
+		if spec.Trainable[i] != got[i] {
+			fmt.Printf("⚠️  %s: manifest trainable flags %v, saved model %v — rebuilding with the saved ones\n",
+				filepath.Base(modelPath), spec.Trainable, got)
+			return
+		}
+	}
+}
+
+// layerActivations returns one activation per layer of nn, for rebuilding it
+// with NewNetwork. Each layer's activation is the first non-empty one among
+// its neurons (not just neuron [0][0], which may be nil). A layer whose
+// neurons disagree gets a warning: the rebuild keeps each neuron's own.
+// override, when its length matches the layer count, wins for every non-empty
+// entry (applyActivationOverride puts it on the neurons); a mismatched
+// override is ignored with a warning.
+func layerActivations(nn *paragon.Network[float32], override []string) []string {
+	if len(override) > 0 && len(override) != len(nn.Layers) {
+		fmt.Printf("⚠️  Ignoring activation override: %d entries for %d layers\n", len(override), len(nn.Layers))
+		override = nil
+	}
+
+	acts := make([]string, len(nn.Layers))
+	for i, L := range nn.Layers {
+		detected := ""
+		mixed := false
+		for y := 0; y < L.Height && y < len(L.Neurons); y++ {
+			for x := 0; x < L.Width && x < len(L.Neurons[y]); x++ {
+				n := L.Neurons[y][x]
+				if n == nil || n.Activation == "" {
+					continue
+				}
+				if detected == "" {
+					detected = n.Activation
+				} else if n.Activation != detected {
+					mixed = true
+				}
+			}
+		}
+
+		switch {
+		case len(override) > 0 && override[i] != "":
+			acts[i] = override[i]
+			if detected != "" && detected != override[i] {
+				fmt.Printf("ℹ️  Layer %d: activation %q overridden with %q\n", i, detected, override[i])
+			}
+		case detected != "":
+			acts[i] = detected
+		default:
+			acts[i] = "linear"
+			if i > 0 {
+				fmt.Printf("⚠️  Layer %d: no neuron records an activation, assuming \"linear\"\n", i)
+			}
+		}
+		if mixed {
+			if len(override) > 0 && override[i] != "" {
+				fmt.Printf("ℹ️  Layer %d: neurons have differing activations; all set to %q by --activations\n", i, acts[i])
+			} else {
+				fmt.Printf("⚠️  Layer %d: neurons have differing activations; each keeps its own — pass --activations to set one\n", i)
+			}
+		}
+	}
+	return acts
+}
+
+// rebuildFloat32 turns a model from paragon.LoadNamedNetworkFromJSONFile into
+// a fresh NewNetwork-built float32 network with the same topology and
+// weights. Loaded networks lack the internal buffers NewNetwork sets up (GPU
+// init in particular needs them), so every load path goes through here.
+func rebuildFloat32(loaded any) (*paragon.Network[float32], error) {
+	tmp, ok := loaded.(*paragon.Network[float32])
+	if !ok {
+		return nil, fmt.Errorf("%w (%T)", ErrModelNotFloat32, loaded)
+	}
+
+	shapes := make([]struct{ Width, Height int }, len(tmp.Layers))
+	acts := layerActivations(tmp, activationOverride())
+	trains := layerTrainFlags(tmp)
+	for i, L := range tmp.Layers {
+		shapes[i] = struct{ Width, Height int }{L.Width, L.Height}
+		if !trains[i] {
+			fmt.Printf("ℹ️  Layer %d: locally connected — rebuilding with trainable=false\n", i)
+		}
+	}
+	nn, err := paragon.NewNetwork[float32](shapes, acts, trains)
+	if err != nil {
+		return nil, fmt.Errorf("NewNetwork failed: %w", err)
+	}
+	state, err := tmp.MarshalJSONModel()
+	if err != nil {
+		return nil, fmt.Errorf("MarshalJSONModel failed: %w", err)
+	}
+	if err := nn.UnmarshalJSONModel(state); err != nil {
+		return nil, fmt.Errorf("UnmarshalJSONModel failed: %w", err)
+	}
+	applyActivationOverride(nn, activationOverride())
+	return nn, nil
+}
+
+// applyActivationOverride sets every neuron of each layer with a non-empty
+// override entry to that activation. UnmarshalJSONModel restores each
+// neuron's saved activation over whatever NewNetwork was given, so the
+// override only sticks when applied afterwards. A mismatched override (already
+// warned about by layerActivations) is ignored.
+func applyActivationOverride(nn *paragon.Network[float32], override []string) {
+	if len(override) != len(nn.Layers) {
+		return
+	}
+	for i, L := range nn.Layers {
+		if override[i] == "" {
 			continue
 		}
+		for _, row := range L.Neurons {
+			for _, n := range row {
+				if n != nil {
+					n.Activation = override[i]
+				}
+			}
+		}
+	}
+}
 
-		fmt.Printf("⏱ %s init: %v\n", spec.ID, time.Since(startInit))
+// warmStart builds a fresh network for targetSpec and copies in the weights
+// of the model at sourcePath for every layer whose shape matches the source's
+// layer at the same index (the output layer is paired with the source's output
+// layer, so a deeper target still inherits its classifier). Within a matched
+// layer each bias is copied, and each incoming weight whose source neuron
+// exists in both models, so a layer behind a differently sized one still gets
+// the overlap. Everything else keeps its random init. Per-layer results are
+// logged.
+func warmStart(targetSpec ModelSpec, sourcePath string) (*paragon.Network[float32], error) {
+	nn, err := buildSpecNetwork(targetSpec)
+	if err != nil {
+		return nil, err
+	}
+	src, err := loadFloat32Model(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("source %s: %w", filepath.Base(sourcePath), err)
+	}
 
-		startSave := time.Now()
-		if err := nn.SaveJSON(outPath); err != nil {
-			fmt.Printf("❌ %s save failed: %v\n", spec.ID, err)
+	// Connections are keyed relative to their own layer (1 = previous layer)
+	// so the output layer still matches when the depths differ.
+	type connKey struct{ back, x, y int }
+	transferred := 0
+	for l := 1; l < len(nn.Layers); l++ {
+		T := nn.Layers[l]
+		sl := l
+		if l == len(nn.Layers)-1 {
+			sl = len(src.Layers) - 1
+		}
+		if sl < 1 || sl >= len(src.Layers) || (sl == len(src.Layers)-1 && l != len(nn.Layers)-1) {
+			fmt.Printf("   layer %d (%dx%d): no matching source layer, random init\n", l, T.Width, T.Height)
+			continue
+		}
+		S := src.Layers[sl]
+		if T.Width != S.Width || T.Height != S.Height {
+			fmt.Printf("   layer %d (%dx%d): shape differs from source layer %d (%dx%d), random init\n",
+				l, T.Width, T.Height, sl, S.Width, S.Height)
 			continue
 		}
-		saveDur := time.Since(startSave)
 
-		fi, _ := os.Stat(outPath)
-		spec.Bytes = fi.Size()
-		manifest = append(manifest, spec)
-		fmt.Printf("💾 %s saved → %s (%d bytes) in %v\n", spec.ID, outPath, spec.Bytes, saveDur)
+		var copied, total int
+		for y := 0; y < T.Height; y++ {
+			for x := 0; x < T.Width; x++ {
+				tn, sn := T.Neurons[y][x], S.Neurons[y][x]
+				if tn == nil || sn == nil {
+					continue
+				}
+				tn.Bias = sn.Bias
+				weights := make(map[connKey]float32, len(sn.Inputs))
+				for _, c := range sn.Inputs {
+					weights[connKey{sl - c.SourceLayer, c.SourceX, c.SourceY}] = c.Weight
+				}
+				for i, c := range tn.Inputs {
+					total++
+					if w, ok := weights[connKey{l - c.SourceLayer, c.SourceX, c.SourceY}]; ok {
+						tn.Inputs[i].Weight = w
+						copied++
+					}
+				}
+			}
+		}
+		transferred++
+		fmt.Printf("   layer %d (%dx%d): ✅ biases + %d/%d weights from source layer %d\n", l, T.Width, T.Height, copied, total, sl)
 	}
+	fmt.Printf("🔥 Warm start: %d/%d layer(s) transferred from %s\n", transferred, len(nn.Layers)-1, filepath.Base(sourcePath))
+	return nn, nil
+}
 
-	// 3) Write manifest
-	manPath := filepath.Join(modelDir, "manifest.json")
-	if err := writeJSON(manPath, manifest); err != nil {
-		fmt.Printf("❌ manifest write failed: %v\n", err)
-	} else {
-		fmt.Printf("📜 manifest written → %s\n", manPath)
+// mergeManifest returns existing with updates applied, keyed by Filename:
+// matching entries are replaced in place (keeping Params if the update has
+// none, e.g. a skipped model), new ones are appended, and entries this run
+// didn't touch are kept as-is.
+func mergeManifest(existing, updates []ModelSpec) []ModelSpec {
+	key := func(s ModelSpec) string { return filepath.ToSlash(s.Filename) }
+
+	merged := append([]ModelSpec(nil), existing...)
+	pos := make(map[string]int, len(merged))
+	for i, s := range merged {
+		pos[key(s)] = i
+	}
+	for _, u := range updates {
+		i, ok := pos[key(u)]
+		if !ok {
+			pos[key(u)] = len(merged)
+			merged = append(merged, u)
+			continue
+		}
+		if u.Params == 0 {
+			u.Params = merged[i].Params
+		}
+		merged[i] = u
 	}
+	return merged
+}
 
-	fmt.Printf("✅ Model zoo ready in %v\n", time.Since(start))
+// readManifest loads public/models/manifest.json (nil, nil if it doesn't exist).
+func readManifest(path string) ([]ModelSpec, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var specs []ModelSpec
+	if err := json.Unmarshal(b, &specs); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return specs, nil
 }
 
 func writeJSON(path string, v any) error {
@@ -163,83 +711,111 @@ func writeJSON(path string, v any) error {
 }
 
 // ---- Benchmark: run first 0–9 samples through every saved model ----
-func benchmarkModelsOnDigits(withGpu bool) {
-	modelDir := MustPublicPath("models")
+var flagBenchRepeats = flag.Int("bench-repeats", 20, "Forward passes per digit in the per-model digit benchmark (min/median/p95 reported)")
+
+// DigitTiming is one digit's repeated-forward timing in the digit benchmark.
+type DigitTiming struct {
+	Digit    int     `json:"digit"`
+	Index    int     `json:"index"`
+	Pred     int     `json:"pred"`
+	Repeats  int     `json:"repeats"`
+	MinMS    float64 `json:"min_ms"`
+	MedianMS float64 `json:"median_ms"`
+	P95MS    float64 `json:"p95_ms"`
+	FirstMS  float64 `json:"first_ms"` // the first forward on its own: the post-init outlier
+}
+
+type DigitBenchModel struct {
+	Model       string        `json:"model"`
+	GPUFallback bool          `json:"gpu_fallback,omitempty"`
+	InitMS      float64       `json:"init_ms,omitempty"`
+	Digits      []DigitTiming `json:"digits"`
+}
+
+type DigitBenchReport struct {
+	Device     string            `json:"device"` // cpu | gpu
+	Repeats    int               `json:"repeats"`
+	SampleMode string            `json:"sample_mode"`
+	Digits     []int             `json:"digits"`
+	CreatedAt  time.Time         `json:"created_at"`
+	Models     []DigitBenchModel `json:"models"`
+}
+
+func benchRepeats() int {
+	if !flag.Parsed() || *flagBenchRepeats < 1 {
+		return 20
+	}
+	return *flagBenchRepeats
+}
+
+// runDigitBenchMenu asks for a digit subset and runs the digit benchmark.
+func runDigitBenchMenu(withGpu bool) {
+	digits, ok := promptDigits(bufio.NewReader(os.Stdin))
+	if !ok {
+		return
+	}
+	benchmarkModelsOnDigits(withGpu, digits)
+}
+
+// benchmarkModelsOnDigits times every model on one sample of each digit in
+// digits (nil for all of 0..9).
+func benchmarkModelsOnDigits(withGpu bool, digits []int) {
+	modelDir := ModelsDir()
+	reps := benchRepeats()
+
+	if withGpu && cpuOnly() {
+		fmt.Println("ℹ️  --cpu-only: running the GPU benchmark on CPU.")
+		withGpu = false
+	}
 
 	// Load dataset once
-	images, labels, err := loadMNISTData(MustPublicPath("mnist"))
+	images, labels, err := getMNIST()
 	if err != nil {
 		fmt.Println("❌ Failed to load MNIST:", err)
 		return
 	}
 
-	// First index for each digit 0..9
-	firstIdx := make(map[int]int)
-	for i, lbl := range labels {
-		for d, v := range lbl[0] {
-			if v == 1.0 {
-				if _, seen := firstIdx[d]; !seen {
-					firstIdx[d] = i
-				}
-				break
-			}
-		}
+	// One index for each digit 0..9 (first occurrence unless --sample-nth/--sample-idx)
+	firstIdx, err := sampleIndexPerDigit(labels)
+	if err != nil {
+		fmt.Println("❌ Sample selection:", err)
+		return
 	}
+	if digits == nil {
+		digits = allDigits()
+	}
+	firstIdx = selectDigits(firstIdx, digits)
+	fmt.Printf("🔢 Digit samples: %s, digits %s, %d forward(s) each\n", sampleSelectionLabel(), digitsLabel(digits), reps)
 
-	entries, err := os.ReadDir(modelDir)
+	models, err := listModels(modelDir)
 	if err != nil {
 		fmt.Println("❌ Failed to read models dir:", err)
 		return
 	}
 
-	for _, e := range entries {
-		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") || e.Name() == "manifest.json" {
-			continue
-		}
-		modelPath := filepath.Join(modelDir, e.Name())
-		fmt.Printf("\n📦 Model: %s\n", e.Name())
+	report := DigitBenchReport{
+		Device:     ternary(withGpu, "gpu", "cpu"),
+		Repeats:    reps,
+		SampleMode: sampleSelectionLabel(),
+		Digits:     digits,
+		CreatedAt:  time.Now().UTC(),
+	}
 
-		// 1) Load into a temp network (type-aware) so we can discover shapes/acts
+	for _, name := range models {
+		modelPath := filepath.Join(modelDir, name)
+		fmt.Printf("\n📦 Model: %s\n", name)
+		row := DigitBenchModel{Model: name}
+
+		// 1) Load (type-aware), then 2) rebuild a fresh NewNetwork with the
+		// same topology and 3) copy the weights/biases over
 		loaded, err := paragon.LoadNamedNetworkFromJSONFile(modelPath)
 		if err != nil {
 			fmt.Printf("❌ Load failed: %v\n", err)
 			continue
 		}
-
-		tmp, ok := loaded.(*paragon.Network[float32])
-		if !ok {
-			fmt.Printf("⚠️ %s is not float32, skipping\n", e.Name())
-			continue
-		}
-
-		// 2) Build a fresh network with the same topology using NewNetwork
-		shapes := make([]struct{ Width, Height int }, len(tmp.Layers))
-		acts := make([]string, len(tmp.Layers))
-		train := make([]bool, len(tmp.Layers))
-		for i, L := range tmp.Layers {
-			shapes[i] = struct{ Width, Height int }{L.Width, L.Height}
-			// assume activation consistent per layer; read first neuron
-			a := "linear"
-			if L.Height > 0 && L.Width > 0 && L.Neurons[0][0] != nil {
-				a = L.Neurons[0][0].Activation
-			}
-			acts[i], train[i] = a, true
-		}
-		nn, err := paragon.NewNetwork[float32](shapes, acts, train)
-		if err != nil {
-			fmt.Printf("❌ NewNetwork failed: %v\n", err)
-			continue
-		}
-		nn.TypeName = "float32"
-
-		// 3) Copy weights/biases from tmp into this fresh, fully-initialized net
-		bytesJSON, err := tmp.MarshalJSONModel()
+		nn, err := rebuildFloat32(loaded)
 		if err != nil {
-			fmt.Printf("❌ MarshalJSONModel failed: %v\n", err)
-			continue
-		}
-		if err := nn.UnmarshalJSONModel(bytesJSON); err != nil {
-			fmt.Printf("❌ UnmarshalJSONModel failed: %v\n", err)
+			fmt.Printf("⚠️ %s: %v, skipping\n", name, err)
 			continue
 		}
 
@@ -247,34 +823,72 @@ func benchmarkModelsOnDigits(withGpu bool) {
 		if withGpu {
 			nn.WebGPUNative, nn.Debug = true, false
 			startGPU := time.Now()
-			if err := nn.InitializeOptimizedGPU(); err != nil {
-				fmt.Printf("⚠️ WebGPU init failed: %v\n   Falling back to CPU…\n", err)
+			err := requireWebGPU()
+			if err == nil {
+				err = nn.InitializeOptimizedGPU()
+			}
+			if err != nil {
+				warnGPUFallback("digit benchmark", err)
 				nn.WebGPUNative = false
+				row.GPUFallback = true
 			} else {
 				fmt.Println("✅ WebGPU initialized")
 			}
 			fmt.Printf("⏱ WebGPU Init Time: %v\n", time.Since(startGPU))
+			recordTiming("init", time.Since(startGPU))
+			row.InitMS = float64(time.Since(startGPU).Microseconds()) / 1000.0
 			// ensure cleanup per model
 			if nn.WebGPUNative {
 				defer nn.CleanupOptimizedGPU()
 			}
 		}
 
-		// 5) Run digits 0..9 (28×28 input — no flattening)
-		for d := 0; d <= 9; d++ {
+		// 5) Run the selected digits (28×28 input — no flattening)
+		for _, d := range digits {
 			idx, ok := firstIdx[d]
 			if !ok {
 				fmt.Printf("⚠️ No sample for digit %d\n", d)
 				continue
 			}
 
-			start := time.Now()
-			nn.Forward(images[idx])   // [][]float64, shape 28x28
-			out := nn.ExtractOutput() // []float64
-			elapsed := time.Since(start)
-
-			pred := argmax64(out)
-			fmt.Printf("Digit %d → pred=%d ⏱ %v\n", d, pred, elapsed)
+			// Repeat the forward: one sample is dominated by noise, and on
+			// GPU the first pass after init is an outlier.
+			samples := make([]float64, reps)
+			var out []float64
+			for r := 0; r < reps; r++ {
+				start := time.Now()
+				nn.Forward(images[idx])  // [][]float64, shape 28x28
+				out = nn.ExtractOutput() // []float64
+				samples[r] = float64(time.Since(start).Nanoseconds()) / 1e6
+			}
+			sorted := append([]float64(nil), samples...)
+			sort.Float64s(sorted)
+			dt := DigitTiming{
+				Digit:    d,
+				Index:    idx,
+				Pred:     argmax64(out),
+				Repeats:  reps,
+				MinMS:    sorted[0],
+				MedianMS: percentile(sorted, 50),
+				P95MS:    percentile(sorted, 95),
+				FirstMS:  samples[0],
+			}
+			row.Digits = append(row.Digits, dt)
+			fmt.Printf("Digit %d → pred=%d ⏱ min %.3fms | median %.3fms | p95 %.3fms (first %.3fms)\n",
+				d, dt.Pred, dt.MinMS, dt.MedianMS, dt.P95MS, dt.FirstMS)
 		}
+		report.Models = append(report.Models, row)
+	}
+
+	outDir, err := EnsurePublicDir("analysis")
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	jsonPath := filepath.Join(outDir, fmt.Sprintf("digit_bench_%s.json", report.Device))
+	if err := writeJSON(jsonPath, report); err != nil {
+		fmt.Printf("❌ Failed to write %s: %v\n", jsonPath, err)
+		return
 	}
+	fmt.Printf("💾 Written → %s\n", jsonPath)
 }
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/openfluke/paragon/v3"
+)
+
+// TestRebuildFloat32RoundTrip saves a spec network, loads it back the way
+// the menus do and checks rebuildFloat32 keeps every weight, bias and the
+// forward output.
+func TestRebuildFloat32RoundTrip(t *testing.T) {
+	spec := ModelSpec{ID: "T", Layers: []string{"784", "16", "10"}}
+	nn, err := buildSpecNetwork(spec)
+	if err != nil {
+		t.Fatalf("buildSpecNetwork: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "mnist_T.json")
+	if err := nn.SaveJSON(path); err != nil {
+		t.Fatalf("SaveJSON: %v", err)
+	}
+	loaded, err := paragon.LoadNamedNetworkFromJSONFile(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	got, err := rebuildFloat32(loaded)
+	if err != nil {
+		t.Fatalf("rebuildFloat32: %v", err)
+	}
+
+	if len(got.Layers) != len(nn.Layers) {
+		t.Fatalf("%d layers, want %d", len(got.Layers), len(nn.Layers))
+	}
+	for l := 1; l < len(nn.Layers); l++ {
+		for y, row := range nn.Layers[l].Neurons {
+			for x, want := range row {
+				have := got.Layers[l].Neurons[y][x]
+				if have.Bias != want.Bias {
+					t.Fatalf("layer %d (%d,%d): bias %v, want %v", l, x, y, have.Bias, want.Bias)
+				}
+				if len(have.Inputs) != len(want.Inputs) {
+					t.Fatalf("layer %d (%d,%d): %d inputs, want %d", l, x, y, len(have.Inputs), len(want.Inputs))
+				}
+				for i, c := range want.Inputs {
+					if have.Inputs[i] != c {
+						t.Fatalf("layer %d (%d,%d) input %d: %+v, want %+v", l, x, y, i, have.Inputs[i], c)
+					}
+				}
+			}
+		}
+	}
+
+	img := make([][]float64, 28)
+	for y := range img {
+		img[y] = make([]float64, 28)
+		for x := range img[y] {
+			img[y][x] = float64((x*7+y*3)%11) / 10
+		}
+	}
+	nn.Forward(img)
+	want := nn.ExtractOutput()
+	got.Forward(img)
+	have := got.ExtractOutput()
+	if maxAbs, _ := driftMaxAndMAE(want, have); maxAbs != 0 {
+		t.Fatalf("forward output differs by up to %g", maxAbs)
+	}
+}
+
+func TestRebuildFloat32RejectsOtherTypes(t *testing.T) {
+	for _, loaded := range []any{&paragon.Network[float64]{}, "not a network"} {
+		nn, err := rebuildFloat32(loaded)
+		if !errors.Is(err, ErrModelNotFloat32) || nn != nil {
+			t.Fatalf("rebuildFloat32(%T) = %v, %v; want ErrModelNotFloat32", loaded, nn, err)
+		}
+	}
+}
+
+// TestRebuildFloat32AppliesActivationOverride checks --activations reaches
+// the neurons of the rebuilt network, not just NewNetwork's arguments.
+func TestRebuildFloat32AppliesActivationOverride(t *testing.T) {
+	orig := *flagActivations
+	*flagActivations = ",tanh,"
+	t.Cleanup(func() { *flagActivations = orig })
+
+	nn, err := buildSpecNetwork(ModelSpec{ID: "T", Layers: []string{"784", "16", "10"}})
+	if err != nil {
+		t.Fatalf("buildSpecNetwork: %v", err)
+	}
+	want := nn.Layers[2].Neurons[0][0].Activation
+	if nn.Layers[1].Neurons[0][0].Activation == "tanh" {
+		t.Fatal("spec network already uses tanh; pick another override")
+	}
+	path := filepath.Join(t.TempDir(), "mnist_T.json")
+	if err := nn.SaveJSON(path); err != nil {
+		t.Fatalf("SaveJSON: %v", err)
+	}
+	loaded, err := paragon.LoadNamedNetworkFromJSONFile(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	got, err := rebuildFloat32(loaded)
+	if err != nil {
+		t.Fatalf("rebuildFloat32: %v", err)
+	}
+	for y, row := range got.Layers[1].Neurons {
+		for x, n := range row {
+			if n.Activation != "tanh" {
+				t.Fatalf("layer 1 (%d,%d): activation %q, want \"tanh\"", x, y, n.Activation)
+			}
+		}
+	}
+	if a := got.Layers[2].Neurons[0][0].Activation; a != want {
+		t.Fatalf("layer 2: activation %q, want the saved %q", a, want)
+	}
+}
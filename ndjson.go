@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// --ndjson streams result events (training epochs, compare digits, telemetry
+// models) to stdout as one JSON object per line while the run is going, so
+// log processors can follow progress instead of waiting for the final file.
+// Stdout then carries nothing but events: setupNDJSON moves all other output
+// (menus, progress, pretty-printed JSON) to stderr.
+var (
+	flagNDJSON = flag.Bool("ndjson", false, "Emit result events as single-line JSON objects on stdout as they happen (other output goes to stderr)")
+
+	ndjsonMu  sync.Mutex
+	ndjsonOut = os.Stdout
+)
+
+func ndjsonEnabled() bool {
+	return flag.Parsed() && *flagNDJSON
+}
+
+// setupNDJSON keeps the real stdout for events and points os.Stdout at
+// stderr, so every fmt.Print* in the program lands there. Call it once,
+// right after flag.Parse.
+func setupNDJSON() {
+	if !ndjsonEnabled() {
+		return
+	}
+	ndjsonOut = os.Stdout
+	os.Stdout = os.Stderr
+}
+
+// emitEvent writes v as one NDJSON line with a "type" discriminator and a
+// "ts" timestamp added. v should marshal to a JSON object (a struct or map);
+// its fields are inlined next to type and ts. No-op without --ndjson.
+func emitEvent(typ string, v any) {
+	if !ndjsonEnabled() {
+		return
+	}
+	line := map[string]any{}
+	if bz, err := json.Marshal(v); err == nil {
+		_ = json.Unmarshal(bz, &line)
+	}
+	line["type"] = typ
+	line["ts"] = time.Now().UTC().Format(time.RFC3339Nano)
+
+	bz, err := json.Marshal(line)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  ndjson %s: %v\n", typ, err)
+		return
+	}
+	ndjsonMu.Lock()
+	defer ndjsonMu.Unlock()
+	ndjsonOut.Write(append(bz, '\n'))
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openfluke/paragon/v3"
+)
+
+// Partial offload: run the first K hidden layers on the GPU and the rest on
+// the CPU, against an all-CPU reference.
+//
+// paragon (v3.1.4) has no per-layer device placement — a network is either
+// WebGPUNative or not. The split is emulated by cutting the model into two
+// networks at layer K (splitNetwork) and handing the prefix's output to the
+// suffix on the host (splitForward), which is also what a real partial
+// offload does at the boundary. If paragon gains per-layer placement, those
+// two functions are the hook point: replace them with one network configured
+// per layer and the comparison and reporting below stay as they are.
+
+// OffloadDigit is one digit sample through the reference and the split.
+type OffloadDigit struct {
+	Digit     int     `json:"digit"`
+	Idx       int     `json:"idx"`
+	RefPred   int     `json:"ref_pred"`
+	SplitPred int     `json:"split_pred"`
+	RefMS     float64 `json:"ref_ms"`
+	SplitMS   float64 `json:"split_ms"` // GPUMS + CPUMS + handoff
+	GPUMS     float64 `json:"gpu_ms"`   // prefix forward + readback
+	CPUMS     float64 `json:"cpu_ms"`   // suffix forward
+	MaxAbs    float64 `json:"max_abs"`
+	MAE       float64 `json:"mae"`
+}
+
+// OffloadResult is one model at one split point.
+type OffloadResult struct {
+	Model        string         `json:"model"`
+	SplitLayer   int            `json:"split_layer"` // last layer computed on the GPU
+	GPULayers    int            `json:"gpu_layers"`  // weighted layers on the GPU
+	CPULayers    int            `json:"cpu_layers"`  // weighted layers on the CPU
+	GPUInitOK    bool           `json:"gpu_init_ok"`
+	GPUInitMS    float64        `json:"gpu_init_ms"`
+	GPUInitError string         `json:"gpu_init_error,omitempty"`
+	PerDigit     []OffloadDigit `json:"per_digit"`
+	MeanMAE      float64        `json:"mean_mae"`
+	MaxDrift     float64        `json:"max_drift"`
+	AgreeCount   int            `json:"agree_count"`
+	MeanRefMS    float64        `json:"mean_ref_ms"`
+	MeanSplitMS  float64        `json:"mean_split_ms"`
+}
+
+// splitNetwork cuts nn after layer k (1 ≤ k < output layer) into a prefix
+// with layers 0..k and a suffix whose input layer is k, both carrying nn's
+// weights, biases, trainable flags and per-neuron activations.
+func splitNetwork(nn *paragon.Network[float32], k int) (prefix, suffix *paragon.Network[float32], err error) {
+	if k < 1 || k >= nn.OutputLayer {
+		return nil, nil, fmt.Errorf("split layer %d out of range 1..%d", k, nn.OutputLayer-1)
+	}
+	acts := layerActivations(nn, nil)
+	trains := layerTrainFlags(nn)
+	sub := func(from, to int) (*paragon.Network[float32], error) {
+		n := to - from + 1
+		shapes := make([]struct{ Width, Height int }, n)
+		for i := 0; i < n; i++ {
+			L := nn.Layers[from+i]
+			shapes[i] = struct{ Width, Height int }{L.Width, L.Height}
+		}
+		out, err := paragon.NewNetwork[float32](shapes, acts[from:to+1], trains[from:to+1])
+		if err != nil {
+			return nil, err
+		}
+		for i := 1; i < n; i++ {
+			for y, row := range nn.Layers[from+i].Neurons {
+				for x, src := range row {
+					d := out.Layers[i].Neurons[y][x]
+					d.Bias = src.Bias
+					d.Activation = src.Activation
+					d.Inputs = make([]paragon.Connection[float32], len(src.Inputs))
+					for c, conn := range src.Inputs {
+						conn.SourceLayer -= from
+						d.Inputs[c] = conn
+					}
+				}
+			}
+		}
+		return out, nil
+	}
+	if prefix, err = sub(0, k); err != nil {
+		return nil, nil, fmt.Errorf("prefix: %w", err)
+	}
+	if suffix, err = sub(k, nn.OutputLayer); err != nil {
+		return nil, nil, fmt.Errorf("suffix: %w", err)
+	}
+	return prefix, suffix, nil
+}
+
+// splitForward runs sample through the GPU prefix, reshapes its output to
+// the suffix's input layer and finishes on the CPU.
+func splitForward(prefix, suffix *paragon.Network[float32], sample [][]float64) (out []float64, gpu, cpu time.Duration) {
+	start := time.Now()
+	prefix.Forward(sample)
+	flat := prefix.ExtractOutput()
+	gpu = time.Since(start)
+
+	in := suffix.Layers[suffix.InputLayer]
+	boundary := make([][]float64, in.Height)
+	for y := range boundary {
+		boundary[y] = flat[y*in.Width : (y+1)*in.Width]
+	}
+	start = time.Now()
+	suffix.Forward(boundary)
+	out = suffix.ExtractOutput()
+	return out, gpu, time.Since(start)
+}
+
+// runOffloadCompare compares the model at modelPath on the CPU against a
+// split at layer k over the digit samples.
+func runOffloadCompare(modelPath string, k int, images [][][]float64, firstIdx map[int]int) (OffloadResult, error) {
+	res := OffloadResult{Model: filepath.Base(modelPath), SplitLayer: k}
+
+	ref, err := loadFloat32Model(modelPath)
+	if err != nil {
+		return res, err
+	}
+	ref.WebGPUNative = false
+	prefix, suffix, err := splitNetwork(ref, k)
+	if err != nil {
+		return res, err
+	}
+	res.GPULayers, res.CPULayers = k, ref.OutputLayer-k
+
+	prefix.WebGPUNative = true
+	startInit := time.Now()
+	if cpuOnly() {
+		res.GPUInitError = "disabled by --cpu-only"
+		prefix.WebGPUNative = false
+	} else if gnn, release, err := initGPUNet(prefix); err != nil {
+		res.GPUInitError = err.Error()
+		warnGPUFallback("offload", err)
+		prefix.WebGPUNative = false
+	} else {
+		prefix = gnn
+		defer release()
+		res.GPUInitOK = true
+		if idx, ok := firstIdx[0]; ok {
+			prefix.Forward(images[idx])
+			_ = prefix.ExtractOutput()
+		}
+	}
+	res.GPUInitMS = float64(time.Since(startInit).Microseconds()) / 1000.0
+	suffix.WebGPUNative = false
+
+	ms := func(d time.Duration) float64 { return float64(d.Microseconds()) / 1000.0 }
+	var sumMAE, sumRef, sumSplit float64
+	for d := 0; d <= 9; d++ {
+		idx, ok := firstIdx[d]
+		if !ok {
+			continue
+		}
+		sample := images[idx]
+
+		startRef := time.Now()
+		ref.Forward(sample)
+		outRef := ref.ExtractOutput()
+		refDur := time.Since(startRef)
+
+		startSplit := time.Now()
+		outSplit, gpuDur, cpuDur := splitForward(prefix, suffix, sample)
+		splitDur := time.Since(startSplit)
+
+		maxAbs, mae := driftMaxAndMAE(outRef, outSplit)
+		od := OffloadDigit{
+			Digit: d, Idx: idx,
+			RefPred: argmax64(outRef), SplitPred: argmax64(outSplit),
+			RefMS: ms(refDur), SplitMS: ms(splitDur), GPUMS: ms(gpuDur), CPUMS: ms(cpuDur),
+			MaxAbs: maxAbs, MAE: mae,
+		}
+		res.PerDigit = append(res.PerDigit, od)
+		sumMAE += mae
+		sumRef += od.RefMS
+		sumSplit += od.SplitMS
+		if maxAbs > res.MaxDrift {
+			res.MaxDrift = maxAbs
+		}
+		if od.RefPred == od.SplitPred {
+			res.AgreeCount++
+		}
+	}
+	n := float64(len(res.PerDigit))
+	res.MeanMAE = safeDiv(sumMAE, n)
+	res.MeanRefMS = safeDiv(sumRef, n)
+	res.MeanSplitMS = safeDiv(sumSplit, n)
+	return res, nil
+}
+
+// runOffloadPrompt asks for a split point (or every split) for one model,
+// prints the comparison and writes public/analysis/offload_<model>.json.
+func runOffloadPrompt(reader *bufio.Reader, modelPath string) {
+	nn, err := loadFloat32Model(modelPath)
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	last := nn.OutputLayer - 1
+	if last < 1 {
+		fmt.Println("❌ The model has no hidden layer to split at")
+		return
+	}
+
+	fmt.Printf("GPU runs layers 1..K, CPU the rest. K [1-%d, all] (default all): ", last)
+	raw, _ := reader.ReadString('\n')
+	sel := strings.TrimSpace(strings.ToLower(raw))
+	var splits []int
+	if sel == "" || sel == "all" {
+		for k := 1; k <= last; k++ {
+			splits = append(splits, k)
+		}
+	} else {
+		k, err := strconv.Atoi(sel)
+		if err != nil || k < 1 || k > last {
+			fmt.Println("❌ Invalid split")
+			return
+		}
+		splits = []int{k}
+	}
+
+	images, labels, err := getMNIST()
+	if err != nil {
+		fmt.Println("❌ Failed to load MNIST:", err)
+		return
+	}
+	firstIdx, err := sampleIndexPerDigit(labels)
+	if err != nil {
+		fmt.Println("❌ Sample selection:", err)
+		return
+	}
+
+	var results []OffloadResult
+	fmt.Printf("\n📦 Model: %s (reference: all layers on CPU)\n", modelPath)
+	fmt.Printf("%-7s | %-6s | %-10s | %-10s | %-6s | %-9s | %-9s\n", "Split", "GPU", "Mean MAE", "Max drift", "Agree", "Ref ms", "Split ms")
+	fmt.Println("-------------------------------------------------------------------------------")
+	for _, k := range splits {
+		res, err := runOffloadCompare(modelPath, k, images, firstIdx)
+		if err != nil {
+			fmt.Printf("⚠️  split %d: %v\n", k, err)
+			continue
+		}
+		gpu := "ok"
+		if !res.GPUInitOK {
+			gpu = "cpu"
+		}
+		fmt.Printf("%-7s | %-6s | %-10.2e | %-10.2e | %-6s | %-9.3f | %-9.3f\n",
+			fmt.Sprintf("%d|%d", res.GPULayers, res.CPULayers), gpu, res.MeanMAE, res.MaxDrift,
+			fmt.Sprintf("%d/%d", res.AgreeCount, len(res.PerDigit)), res.MeanRefMS, res.MeanSplitMS)
+		results = append(results, res)
+	}
+	fmt.Println("-------------------------------------------------------------------------------")
+	if len(results) == 0 {
+		return
+	}
+	if !results[0].GPUInitOK {
+		fmt.Printf("⚠️  GPU prefix ran on CPU (%s) — drift numbers are CPU vs CPU.\n", results[0].GPUInitError)
+	}
+
+	outDir, err := EnsurePublicDir("analysis")
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	base := strings.TrimSuffix(filepath.Base(modelPath), filepath.Ext(modelPath))
+	jsonPath := filepath.Join(outDir, "offload_"+base+".json")
+	if err := writeJSON(jsonPath, results); err != nil {
+		fmt.Printf("❌ Failed to write %s: %v\n", jsonPath, err)
+		return
+	}
+	fmt.Printf("💾 Written → %s\n", jsonPath)
+}
@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+// TestSplitNetworkMatchesWhole splits a model with a locally connected layer
+// and a layer of mixed activations, and checks prefix+suffix on the CPU give
+// the same output as the whole network.
+func TestSplitNetworkMatchesWhole(t *testing.T) {
+	nn, err := buildSpecNetwork(ModelSpec{ID: "T", Layers: []string{"784", "32", "16", "10"},
+		Trainable: []bool{true, false, true, true}})
+	if err != nil {
+		t.Fatalf("buildSpecNetwork: %v", err)
+	}
+	nn.Layers[2].Neurons[0][3].Activation = "tanh"
+	nn.WebGPUNative = false
+
+	img := make([][]float64, 28)
+	for y := range img {
+		img[y] = make([]float64, 28)
+		for x := range img[y] {
+			img[y][x] = float64((x*5+y*3)%13) / 12
+		}
+	}
+	nn.Forward(img)
+	want := nn.ExtractOutput()
+
+	for k := 1; k < nn.OutputLayer; k++ {
+		prefix, suffix, err := splitNetwork(nn, k)
+		if err != nil {
+			t.Fatalf("split at %d: %v", k, err)
+		}
+		got, _, _ := splitForward(prefix, suffix, img)
+		if maxAbs, _ := driftMaxAndMAE(want, got); maxAbs != 0 {
+			t.Fatalf("split at %d: output differs by up to %g", k, maxAbs)
+		}
+	}
+}
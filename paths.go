@@ -1,9 +1,11 @@
 package main
 
 import (
+	"embed"
 	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -11,6 +13,13 @@ import (
 	"sync"
 )
 
+// Minimal public tree (index.html, models/, reports/) baked into the binary so
+// a fresh download works with zero setup. It is copied into the base dir on
+// first use; existing files are never overwritten.
+//
+//go:embed all:assets/public
+var defaultPublic embed.FS
+
 var (
 	baseOnce sync.Once
 	baseDir  string
@@ -18,8 +27,40 @@ var (
 
 	// optional CLI override (wire in main() if you want)
 	flagBaseDir = flag.String("base", "", "Base data directory (overrides auto-detect)")
+
+	// Model zoo location, default <base>/models. Lets the menus run against an
+	// extracted archive or any other directory of models.
+	flagModelsDir     = flag.String("models-dir", "", "Models directory (default <base>/models)")
+	modelsDirMu       sync.Mutex
+	modelsDirOverride string // set at runtime, e.g. after importing an archive
 )
 
+// ModelsDir returns the directory the menus read models from. Precedence:
+// runtime override (SetModelsDir) > PARAGON_MODELS_DIR > --models-dir >
+// <base>/models.
+func ModelsDir() string {
+	modelsDirMu.Lock()
+	o := modelsDirOverride
+	modelsDirMu.Unlock()
+	if o != "" {
+		return o
+	}
+	if v := strings.TrimSpace(os.Getenv("PARAGON_MODELS_DIR")); v != "" {
+		return v
+	}
+	if flag.Parsed() && *flagModelsDir != "" {
+		return *flagModelsDir
+	}
+	return MustPublicPath("models")
+}
+
+// SetModelsDir points ModelsDir at dir for the rest of the session ("" resets).
+func SetModelsDir(dir string) {
+	modelsDirMu.Lock()
+	defer modelsDirMu.Unlock()
+	modelsDirOverride = dir
+}
+
 func BaseDir() (string, error) {
 	baseOnce.Do(func() {
 		// 1) ENV override (highest priority for advanced users)
@@ -48,17 +89,23 @@ func BaseDir() (string, error) {
 			publicDir := filepath.Join(exeDir, "public")
 
 			// Create it if it doesn't exist
-			if err := os.MkdirAll(publicDir, 0755); err != nil {
-				baseErr = fmt.Errorf("failed to create public dir next to exe: %w", err)
+			if err := os.MkdirAll(publicDir, 0755); err == nil {
+				baseDir = publicDir
+				seedPublicDir(baseDir)
 				return
 			}
+		}
 
-			baseDir = publicDir
+		// 4) Fallback for read-only install locations: materialize the
+		// embedded defaults into a temp dir so the tool still starts.
+		tmpDir := filepath.Join(os.TempDir(), "paragon-iso", "public")
+		if err := os.MkdirAll(tmpDir, 0755); err != nil {
+			baseErr = fmt.Errorf("could not create a public dir next to the executable or in %s: %w", tmpDir, err)
 			return
 		}
-
-		// 4) Fallback if we can't determine executable location (shouldn't happen normally)
-		baseErr = errors.New("could not determine executable location")
+		fmt.Printf("ℹ️  Using temporary data dir %s (no writable public dir next to the executable)\n", tmpDir)
+		baseDir = tmpDir
+		seedPublicDir(baseDir)
 	})
 	return baseDir, baseErr
 }
@@ -90,6 +137,35 @@ func EnsurePublicDir(parts ...string) (string, error) {
 	return p, nil
 }
 
+// seedPublicDir copies the embedded default public tree into dir, skipping
+// anything that already exists. Failures are reported but not fatal.
+func seedPublicDir(dir string) {
+	root, _ := fs.Sub(defaultPublic, "assets/public")
+	err := fs.WalkDir(root, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(dir, filepath.FromSlash(p))
+		if d.IsDir() {
+			return os.MkdirAll(dst, 0o755)
+		}
+		if d.Name() == ".keep" {
+			return nil // placeholder so the empty dir gets embedded
+		}
+		if _, err := os.Stat(dst); err == nil {
+			return nil
+		}
+		b, err := fs.ReadFile(root, p)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(dst, b, 0o644)
+	})
+	if err != nil {
+		fmt.Printf("⚠️  Could not seed default public files into %s: %v\n", dir, err)
+	}
+}
+
 func isDir(p string) bool {
 	st, err := os.Stat(p)
 	return err == nil && st.IsDir()
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openfluke/paragon/v3"
+)
+
+// Preprocessing for real-world digit images. MNIST digits are white-on-black,
+// roughly centered in a square 28×28 frame; scans and photos are usually
+// black-on-white and much larger, so without this glue the model sees noise.
+var (
+	flagInvert       = flag.String("invert", "auto", "Image inversion for predictions: auto (detect dark-on-light), yes, no")
+	flagResizeFilter = flag.String("resize-filter", "area", "Resize filter for predictions: area, bilinear, nearest")
+)
+
+// ImageInputOptions controls toModelInputOpts.
+type ImageInputOptions struct {
+	Invert string // auto | yes | no
+	Filter string // area | bilinear | nearest
+}
+
+func defaultImageInputOptions() ImageInputOptions {
+	opts := ImageInputOptions{Invert: "auto", Filter: "area"}
+	if flag.Parsed() {
+		opts.Invert, opts.Filter = *flagInvert, *flagResizeFilter
+	}
+	return opts
+}
+
+func (o ImageInputOptions) validate() error {
+	switch o.Invert {
+	case "auto", "yes", "no":
+	default:
+		return fmt.Errorf("invalid invert %q (want auto, yes or no)", o.Invert)
+	}
+	switch o.Filter {
+	case "area", "bilinear", "nearest":
+	default:
+		return fmt.Errorf("invalid resize filter %q (want area, bilinear or nearest)", o.Filter)
+	}
+	return nil
+}
+
+// toModelInput converts img to a w×h grid of 0..1 intensities the way MNIST
+// looks, using the --invert/--resize-filter defaults.
+func toModelInput(img image.Image, w, h int) [][]float64 {
+	return toModelInputOpts(img, w, h, defaultImageInputOptions())
+}
+
+// toModelInputOpts grayscales img, inverts it if asked (or, for "auto", if
+// its border is mostly light), pads it to the target aspect ratio with the
+// background so the digit isn't stretched, and resizes to w×h.
+func toModelInputOpts(img image.Image, w, h int, opts ImageInputOptions) [][]float64 {
+	gray := grayscale(img)
+
+	invert := opts.Invert == "yes" || (opts.Invert == "auto" && borderMean(gray) > 0.5)
+	if invert {
+		for _, row := range gray {
+			for x := range row {
+				row[x] = 1 - row[x]
+			}
+		}
+	}
+
+	padded := padToAspect(gray, w, h, borderMean(gray))
+	switch opts.Filter {
+	case "nearest":
+		return resizeNearest(padded, w, h)
+	case "bilinear":
+		return resizeBilinear(padded, w, h)
+	default:
+		return resizeArea(padded, w, h)
+	}
+}
+
+func grayscale(img image.Image) [][]float64 {
+	b := img.Bounds()
+	out := make([][]float64, b.Dy())
+	for y := 0; y < b.Dy(); y++ {
+		out[y] = make([]float64, b.Dx())
+		for x := 0; x < b.Dx(); x++ {
+			g := color.Gray16Model.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.Gray16)
+			out[y][x] = float64(g.Y) / 0xffff
+		}
+	}
+	return out
+}
+
+// borderMean is the average intensity of the outermost pixels: the background.
+func borderMean(px [][]float64) float64 {
+	h := len(px)
+	if h == 0 || len(px[0]) == 0 {
+		return 0
+	}
+	w := len(px[0])
+	var sum float64
+	var n int
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if y == 0 || y == h-1 || x == 0 || x == w-1 {
+				sum += px[y][x]
+				n++
+			}
+		}
+	}
+	return sum / float64(n)
+}
+
+// padToAspect centers px in the smallest canvas with the w:h aspect ratio,
+// filling the margin with bg.
+func padToAspect(px [][]float64, w, h int, bg float64) [][]float64 {
+	srcH := len(px)
+	if srcH == 0 {
+		return px
+	}
+	srcW := len(px[0])
+	dstW, dstH := srcW, srcH
+	if srcW*h > srcH*w {
+		dstH = int(math.Ceil(float64(srcW) * float64(h) / float64(w)))
+	} else {
+		dstW = int(math.Ceil(float64(srcH) * float64(w) / float64(h)))
+	}
+	if dstW == srcW && dstH == srcH {
+		return px
+	}
+	offX, offY := (dstW-srcW)/2, (dstH-srcH)/2
+	out := make([][]float64, dstH)
+	for y := range out {
+		out[y] = make([]float64, dstW)
+		for x := range out[y] {
+			sy, sx := y-offY, x-offX
+			if sy >= 0 && sy < srcH && sx >= 0 && sx < srcW {
+				out[y][x] = px[sy][sx]
+			} else {
+				out[y][x] = bg
+			}
+		}
+	}
+	return out
+}
+
+func resizeNearest(px [][]float64, w, h int) [][]float64 {
+	srcH, srcW := len(px), len(px[0])
+	out := make([][]float64, h)
+	for y := range out {
+		out[y] = make([]float64, w)
+		sy := min(y*srcH/h, srcH-1)
+		for x := range out[y] {
+			out[y][x] = px[sy][min(x*srcW/w, srcW-1)]
+		}
+	}
+	return out
+}
+
+func resizeBilinear(px [][]float64, w, h int) [][]float64 {
+	srcH, srcW := len(px), len(px[0])
+	out := make([][]float64, h)
+	for y := range out {
+		out[y] = make([]float64, w)
+		fy := math.Max(0, (float64(y)+0.5)*float64(srcH)/float64(h)-0.5)
+		y0 := min(int(fy), srcH-1)
+		y1 := min(y0+1, srcH-1)
+		dy := fy - float64(y0)
+		for x := range out[y] {
+			fx := math.Max(0, (float64(x)+0.5)*float64(srcW)/float64(w)-0.5)
+			x0 := min(int(fx), srcW-1)
+			x1 := min(x0+1, srcW-1)
+			dx := fx - float64(x0)
+			top := px[y0][x0]*(1-dx) + px[y0][x1]*dx
+			bot := px[y1][x0]*(1-dx) + px[y1][x1]*dx
+			out[y][x] = top*(1-dy) + bot*dy
+		}
+	}
+	return out
+}
+
+// resizeArea averages every source pixel a target pixel covers (weighted by
+// overlap): the right filter for shrinking a large scan, where nearest and
+// bilinear skip most of the strokes.
+func resizeArea(px [][]float64, w, h int) [][]float64 {
+	srcH, srcW := len(px), len(px[0])
+	if srcW < w || srcH < h {
+		return resizeBilinear(px, w, h)
+	}
+	sx, sy := float64(srcW)/float64(w), float64(srcH)/float64(h)
+	out := make([][]float64, h)
+	for y := range out {
+		out[y] = make([]float64, w)
+		y0, y1 := float64(y)*sy, float64(y+1)*sy
+		for x := range out[y] {
+			x0, x1 := float64(x)*sx, float64(x+1)*sx
+			var sum, area float64
+			for iy := int(y0); iy < srcH && float64(iy) < y1; iy++ {
+				wy := math.Min(y1, float64(iy+1)) - math.Max(y0, float64(iy))
+				for ix := int(x0); ix < srcW && float64(ix) < x1; ix++ {
+					wx := math.Min(x1, float64(ix+1)) - math.Max(x0, float64(ix))
+					sum += px[iy][ix] * wx * wy
+					area += wx * wy
+				}
+			}
+			out[y][x] = safeDiv(sum, area)
+		}
+	}
+	return out
+}
+
+// ImagePrediction is the model's answer for one image file.
+type ImagePrediction struct {
+	File   string    `json:"file"`
+	Pred   int       `json:"pred"`
+	Conf   float64   `json:"confidence"`
+	Output []float64 `json:"output"`
+	Error  string    `json:"error,omitempty"`
+}
+
+func predictImage(nn *paragon.Network[float32], imgPath string, opts ImageInputOptions) (ImagePrediction, error) {
+	p := ImagePrediction{File: imgPath}
+	f, err := os.Open(imgPath)
+	if err != nil {
+		return p, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return p, fmt.Errorf("decode %s: %w", filepath.Base(imgPath), err)
+	}
+	in := nn.Layers[0]
+	nn.Forward(toModelInputOpts(img, in.Width, in.Height, opts))
+	p.Output = nn.ExtractOutput()
+	p.Pred = argmax64(p.Output)
+	if p.Pred < 0 {
+		return p, fmt.Errorf("model produced no output for %s", filepath.Base(imgPath))
+	}
+	p.Conf = p.Output[p.Pred]
+	return p, nil
+}
+
+// predictImageFile runs one image (png/jpeg/gif) through the model on CPU.
+func predictImageFile(modelPath, imgPath string, opts ImageInputOptions) (ImagePrediction, error) {
+	if err := opts.validate(); err != nil {
+		return ImagePrediction{File: imgPath}, err
+	}
+	nn, err := loadFloat32Model(modelPath)
+	if err != nil {
+		return ImagePrediction{File: imgPath}, err
+	}
+	return predictImage(nn, imgPath, opts)
+}
+
+// predictImageDir runs every png/jpeg/gif in dir (non-recursive, sorted by
+// name) through the model, loading it once. Undecodable files are reported
+// per entry rather than failing the whole directory.
+func predictImageDir(modelPath, dir string, opts ImageInputOptions) ([]ImagePrediction, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".png", ".jpg", ".jpeg", ".gif":
+			if !e.IsDir() {
+				files = append(files, filepath.Join(dir, e.Name()))
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .png/.jpg/.gif images in %s", dir)
+	}
+	sort.Strings(files)
+
+	nn, err := loadFloat32Model(modelPath)
+	if err != nil {
+		return nil, err
+	}
+	var out []ImagePrediction
+	for _, f := range files {
+		p, err := predictImage(nn, f, opts)
+		if err != nil {
+			p.Error = err.Error()
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func runPredictMenu() {
+	modelDir := ModelsDir()
+	models, _ := listModels(modelDir)
+	if len(models) == 0 {
+		fmt.Println("❌ No models found in public/models/")
+		return
+	}
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Println("\nAvailable models:")
+	for i, m := range models {
+		fmt.Printf("%d) %s%s\n", i+1, m, modelChoiceLabel(modelDir, m))
+	}
+	fmt.Print("Select model: ")
+	choiceRaw, _ := reader.ReadString('\n')
+	idx, err := strconv.Atoi(strings.TrimSpace(choiceRaw))
+	if err != nil || idx < 1 || idx > len(models) {
+		fmt.Println("❌ Invalid choice")
+		return
+	}
+	modelPath := filepath.Join(modelDir, models[idx-1])
+
+	fmt.Print("Image file or directory: ")
+	pathRaw, _ := reader.ReadString('\n')
+	target := strings.TrimSpace(pathRaw)
+	if target == "" {
+		fmt.Println("❌ path required")
+		return
+	}
+
+	opts := defaultImageInputOptions()
+	fmt.Printf("Invert [auto/yes/no] (default %s): ", opts.Invert)
+	if v, _ := reader.ReadString('\n'); strings.TrimSpace(v) != "" {
+		opts.Invert = strings.ToLower(strings.TrimSpace(v))
+	}
+	fmt.Printf("Resize filter [area/bilinear/nearest] (default %s): ", opts.Filter)
+	if v, _ := reader.ReadString('\n'); strings.TrimSpace(v) != "" {
+		opts.Filter = strings.ToLower(strings.TrimSpace(v))
+	}
+
+	var preds []ImagePrediction
+	if isDir(target) {
+		preds, err = predictImageDir(modelPath, target, opts)
+	} else {
+		var p ImagePrediction
+		p, err = predictImageFile(modelPath, target, opts)
+		preds = []ImagePrediction{p}
+	}
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	for _, p := range preds {
+		if p.Error != "" {
+			fmt.Printf("⚠️  %s: %s\n", filepath.Base(p.File), p.Error)
+			continue
+		}
+		fmt.Printf("🔮 %s → %d (%.1f%%)  top-3: %s\n", filepath.Base(p.File), p.Pred, p.Conf*100, formatTopK(p.Output, 3))
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// --db appends evaluation and benchmark results to a SQLite file so runs can
+// be queried over time. The driver is only compiled in with `-tags sqlite`
+// (see resultsdb_sqlite.go); without it --db reports that and is ignored.
+var flagDB = flag.String("db", "", "Also record eval/bench results in this SQLite file (needs a build with -tags sqlite)")
+
+// sqliteDriver is the database/sql driver name, set by resultsdb_sqlite.go.
+var sqliteDriver string
+
+var (
+	resultsDBOnce sync.Once
+	resultsDBConn *sql.DB
+)
+
+const resultsSchema = `
+CREATE TABLE IF NOT EXISTS eval_results (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	machine_id   TEXT NOT NULL,
+	model        TEXT NOT NULL,
+	evaluated_at TEXT NOT NULL,
+	train_score  REAL,
+	test_score   REAL,
+	gpu          INTEGER,
+	gpu_fallback INTEGER,
+	split        TEXT
+);
+CREATE INDEX IF NOT EXISTS eval_results_key ON eval_results (machine_id, model, evaluated_at);
+
+CREATE TABLE IF NOT EXISTS bench_results (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	machine_id   TEXT NOT NULL,
+	started_at   TEXT NOT NULL,
+	type         TEXT NOT NULL,
+	single_ops   INTEGER,
+	multi_ops    INTEGER,
+	duration_sec REAL,
+	run_mode     TEXT,
+	filter       TEXT
+);
+CREATE INDEX IF NOT EXISTS bench_results_key ON bench_results (machine_id, type, started_at);
+`
+
+// openResultsDB opens (creating if needed) the results database at path.
+func openResultsDB(path string) (*sql.DB, error) {
+	if sqliteDriver == "" {
+		return nil, fmt.Errorf("built without SQLite support; rebuild with -tags sqlite")
+	}
+	db, err := sql.Open(sqliteDriver, path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(resultsSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init schema in %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// resultsDB returns the --db database, opening it on first use, or nil when
+// --db is unset or the open failed (which is reported once).
+func resultsDB() *sql.DB {
+	if !flag.Parsed() || *flagDB == "" {
+		return nil
+	}
+	resultsDBOnce.Do(func() {
+		db, err := openResultsDB(*flagDB)
+		if err != nil {
+			fmt.Printf("⚠️  --db %s: %v — results are not recorded\n", *flagDB, err)
+			return
+		}
+		resultsDBConn = db
+	})
+	return resultsDBConn
+}
+
+// insertEvalResult adds one evaluation row.
+func insertEvalResult(db *sql.DB, machineID string, r EvalResult) error {
+	_, err := db.Exec(`INSERT INTO eval_results
+		(machine_id, model, evaluated_at, train_score, test_score, gpu, gpu_fallback, split)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		machineID, r.Model, r.EvaluatedAt.UTC().Format(time.RFC3339Nano),
+		r.TrainScore, r.TestScore, r.GPU, r.GPUFallback, r.Split)
+	return err
+}
+
+// insertBenchInfo adds one row per benchmarked type, in one transaction.
+func insertBenchInfo(db *sql.DB, machineID string, b BenchInfo) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, r := range b.Results {
+		if _, err := tx.Exec(`INSERT INTO bench_results
+			(machine_id, started_at, type, single_ops, multi_ops, duration_sec, run_mode, filter)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			machineID, b.StartedAt.UTC().Format(time.RFC3339Nano), r.Type,
+			r.Single, r.Multi, b.DurationSec, b.RunMode, b.Filter); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// recordEvalToDB and recordBenchToDB are what the eval and bench paths call;
+// they do nothing without --db.
+func recordEvalToDB(r EvalResult) {
+	if db := resultsDB(); db != nil {
+		if err := insertEvalResult(db, hashSystemInfo(Collect()), r); err != nil {
+			fmt.Printf("⚠️  Could not record eval result in %s: %v\n", *flagDB, err)
+		}
+	}
+}
+
+func recordBenchToDB(b BenchInfo) {
+	if db := resultsDB(); db != nil {
+		if err := insertBenchInfo(db, hashSystemInfo(b.System), b); err != nil {
+			fmt.Printf("⚠️  Could not record bench results in %s: %v\n", *flagDB, err)
+		}
+	}
+}
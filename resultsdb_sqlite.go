@@ -0,0 +1,10 @@
+//go:build sqlite
+
+package main
+
+// Built with -tags sqlite: register the pure-Go SQLite driver for --db.
+import _ "modernc.org/sqlite"
+
+func init() {
+	sqliteDriver = "sqlite"
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RunContext is everything needed to reproduce (or explain) a result: which
+// build ran where, with what flags, environment, seeds and input files. Major
+// operations capture one when they start and write it next to their output as
+// <output>.run_context.json.
+type RunContext struct {
+	Operation  string            `json:"operation"` // telemetry | evaluate | bench | batch | …
+	CapturedAt time.Time         `json:"captured_at"`
+	Build      BuildInfo         `json:"build"`
+	System     SystemInfo        `json:"system_info"`
+	GoVersion  string            `json:"go_version"`
+	GOMAXPROCS int               `json:"gomaxprocs"`
+	Args       []string          `json:"args"`
+	Flags      map[string]string `json:"flags,omitempty"` // only flags set on the command line
+	Env        map[string]string `json:"env,omitempty"`   // overrides that change paths/data (PARAGON_DATA_DIR, …)
+	Seeds      map[string]int64  `json:"seeds,omitempty"`
+	Split      string            `json:"split"`
+	MNIST      []FileChecksum    `json:"mnist,omitempty"`
+	Models     []FileChecksum    `json:"models,omitempty"`
+}
+
+// FileChecksum identifies one input file by content.
+type FileChecksum struct {
+	Path   string `json:"path"` // relative to its directory (slash-separated)
+	Bytes  int64  `json:"bytes"`
+	SHA256 string `json:"sha256"`
+}
+
+// runContextEnv are the environment variables that change what a run reads
+// or how it behaves; the ones set are recorded.
+var runContextEnv = []string{
+	"PARAGON_DATA_DIR", "PARAGON_MODELS_DIR", "PARAGON_MAX_SAMPLES",
+	"REPORTS_PARTITION", "XDG_DATA_HOME", "APPDATA",
+}
+
+// runContextSuffix replaces ".json" on the output the context belongs to.
+const runContextSuffix = ".run_context.json"
+
+// isRunContextFile reports whether name is a run context rather than a result.
+func isRunContextFile(name string) bool {
+	return strings.HasSuffix(name, runContextSuffix)
+}
+
+// captureRunContext snapshots the build, system, flags, environment, split,
+// MNIST files and the models directory. Callers fill in Operation, add the
+// seeds their operation uses, and replace Models when they read another dir.
+func captureRunContext() RunContext {
+	rc := RunContext{
+		CapturedAt: time.Now().UTC(),
+		Build:      currentBuild(),
+		System:     Collect(),
+		GoVersion:  runtime.Version(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		Args:       os.Args,
+		Flags:      map[string]string{},
+		Env:        map[string]string{},
+		Seeds:      map[string]int64{},
+		Split:      splitLabel(),
+	}
+	if flag.Parsed() {
+		flag.Visit(func(f *flag.Flag) { rc.Flags[f.Name] = f.Value.String() })
+	}
+	for _, k := range runContextEnv {
+		if v, ok := os.LookupEnv(k); ok {
+			rc.Env[k] = v
+		}
+	}
+	if balanceEnabled() {
+		rc.Seeds["balance"] = balanceSeed
+	}
+	if dir, err := PublicPath("mnist"); err == nil {
+		rc.MNIST = checksumDir(dir, func(name string) bool { return strings.Contains(name, "-ubyte") })
+	}
+	rc.Models = checksumDir(ModelsDir(), func(name string) bool {
+		return strings.HasSuffix(name, ".json") && name != "manifest.json" && name != "status.json"
+	})
+	return rc
+}
+
+// checksumDir hashes the files directly or recursively under dir that keep
+// returns true for. Missing directories give nil.
+func checksumDir(dir string, keep func(name string) bool) []FileChecksum {
+	var out []FileChecksum
+	_ = filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !keep(d.Name()) {
+			return nil
+		}
+		sum, size, err := cachedSHA256(p)
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, p)
+		out = append(out, FileChecksum{Path: filepath.ToSlash(rel), Bytes: size, SHA256: sum})
+		return nil
+	})
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+// Hashing an XL zoo takes seconds, so checksums are reused while a file's
+// size and mtime are unchanged.
+var (
+	sumCacheMu sync.Mutex
+	sumCache   = map[string]cachedSum{}
+)
+
+type cachedSum struct {
+	size    int64
+	modTime time.Time
+	sum     string
+}
+
+func cachedSHA256(path string) (string, int64, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", 0, err
+	}
+	sumCacheMu.Lock()
+	c, ok := sumCache[path]
+	sumCacheMu.Unlock()
+	if ok && c.size == fi.Size() && c.modTime.Equal(fi.ModTime()) {
+		return c.sum, c.size, nil
+	}
+	sum, size, err := fileSHA256(path)
+	if err != nil {
+		return "", 0, err
+	}
+	sumCacheMu.Lock()
+	sumCache[path] = cachedSum{size: fi.Size(), modTime: fi.ModTime(), sum: sum}
+	sumCacheMu.Unlock()
+	return sum, size, nil
+}
+
+// writeRunContext writes rc next to the output at outPath
+// (foo.json → foo.run_context.json).
+func writeRunContext(outPath string, rc RunContext) error {
+	p := strings.TrimSuffix(outPath, filepath.Ext(outPath)) + runContextSuffix
+	return writeJSON(p, rc)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ModelStatus tracks training state per model, kept next to manifest.json in
+// public/models/status.json (keyed by the model's relative name). Models with
+// no entry have never been trained by this tool.
+type ModelStatus struct {
+	Trained       bool      `json:"trained"`
+	BestTestScore float64   `json:"best_test_score"`
+	LastTrainedAt time.Time `json:"last_trained_at,omitempty"`
+}
+
+var statusMu sync.Mutex
+
+func statusPath() string {
+	return filepath.Join(ModelsDir(), "status.json")
+}
+
+// loadModelStatus reads status.json; a missing file is an empty status map.
+func loadModelStatus() map[string]ModelStatus {
+	out := map[string]ModelStatus{}
+	b, err := os.ReadFile(statusPath())
+	if err != nil {
+		return out
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		fmt.Printf("⚠️  Ignoring unreadable %s: %v\n", statusPath(), err)
+		return map[string]ModelStatus{}
+	}
+	return out
+}
+
+// recordTraining marks modelPath as trained, keeping the best test score seen.
+func recordTraining(modelPath string, testScore float64) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+
+	name := modelRelName(modelPath)
+	all := loadModelStatus()
+	st := all[name]
+	st.Trained = true
+	if testScore > st.BestTestScore {
+		st.BestTestScore = testScore
+	}
+	st.LastTrainedAt = time.Now().UTC()
+	all[name] = st
+
+	if err := writeJSON(statusPath(), all); err != nil {
+		fmt.Printf("⚠️  status update failed: %v\n", err)
+	}
+}
+
+// forgetModelStatus drops name from status.json (after delete/reset).
+func forgetModelStatus(name string) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+
+	all := loadModelStatus()
+	if _, ok := all[name]; !ok {
+		return
+	}
+	delete(all, name)
+	if err := writeJSON(statusPath(), all); err != nil {
+		fmt.Printf("⚠️  status update failed: %v\n", err)
+	}
+}
+
+// modelStatusLabel is the annotation shown next to a model in selection lists.
+func modelStatusLabel(all map[string]ModelStatus, name string) string {
+	st, ok := all[name]
+	if !ok || !st.Trained {
+		return "(untrained)"
+	}
+	return fmt.Sprintf("(best test %.2f%%)", st.BestTestScore)
+}
+
+// modelRelName returns modelPath relative to public/models in slash form,
+// falling back to the base name for models that live elsewhere.
+func modelRelName(modelPath string) string {
+	rel, err := filepath.Rel(ModelsDir(), modelPath)
+	if err != nil || !safeModelRelPath(rel) {
+		return filepath.Base(modelPath)
+	}
+	return filepath.ToSlash(rel)
+}
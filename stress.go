@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stressEpsilon is the max-abs drift from the reference run that counts as a
+// mismatch. Repeated forwards on the same device should be bit-identical, so
+// anything above float32 rounding noise is a real instability.
+const stressEpsilon = 1e-6
+
+// maxStressFailures caps how many mismatching iterations are kept verbatim.
+const maxStressFailures = 100
+
+// StressFailure is one iteration whose output drifted from the reference.
+type StressFailure struct {
+	Iter        int     `json:"iter"`
+	Digit       int     `json:"digit"`
+	MaxAbs      float64 `json:"max_abs"`
+	PredChanged bool    `json:"pred_changed"`
+}
+
+// StressResult summarizes a stressModel run.
+type StressResult struct {
+	Model       string          `json:"model"`
+	Device      string          `json:"device"` // cpu | gpu
+	GPUFallback bool            `json:"gpu_fallback,omitempty"`
+	Iters       int             `json:"iters"`
+	Epsilon     float64         `json:"epsilon"`
+	Mismatches  int             `json:"mismatches"`
+	MaxDrift    float64         `json:"max_drift"`
+	ElapsedMS   float64         `json:"elapsed_ms"`
+	Failures    []StressFailure `json:"failures,omitempty"` // first maxStressFailures only
+}
+
+func (r StressResult) ToJSON() string {
+	bz, _ := json.MarshalIndent(r, "", "  ")
+	return string(bz)
+}
+
+// stressModel runs modelPath's forward iters times, cycling through the ten
+// digit samples, and checks every output against that sample's first output.
+// Some drivers only return wrong results under sustained load; this is how to
+// catch them.
+func stressModel(modelPath string, iters int, useGPU bool) (StressResult, error) {
+	res := StressResult{Model: modelPath, Device: "cpu", Iters: iters, Epsilon: stressEpsilon}
+	if iters < 1 {
+		return res, fmt.Errorf("iters must be ≥ 1")
+	}
+
+	images, labels, err := getMNIST()
+	if err != nil {
+		return res, fmt.Errorf("failed to load MNIST: %w", err)
+	}
+	firstIdx, err := sampleIndexPerDigit(labels)
+	if err != nil {
+		return res, fmt.Errorf("select samples: %w", err)
+	}
+	var digits []int
+	for d := 0; d <= 9; d++ {
+		if _, ok := firstIdx[d]; ok {
+			digits = append(digits, d)
+		}
+	}
+	if len(digits) == 0 {
+		return res, fmt.Errorf("no digit samples selected")
+	}
+
+	nn, err := loadFloat32Model(modelPath)
+	if err != nil {
+		return res, err
+	}
+	if useGPU && !cpuOnly() {
+		res.Device = "gpu"
+		nn.WebGPUNative, nn.Debug = true, false
+		startInit := time.Now()
+		err := requireWebGPU()
+		if err == nil {
+			err = nn.InitializeOptimizedGPU()
+		}
+		if err != nil {
+			warnGPUFallback("stress test", err)
+			nn.WebGPUNative = false
+			res.GPUFallback = true
+		} else {
+			defer nn.CleanupOptimizedGPU()
+		}
+		recordTiming("init", time.Since(startInit))
+	}
+
+	refs := make(map[int][]float64, len(digits))
+	start := time.Now()
+	for i := 0; i < iters; i++ {
+		d := digits[i%len(digits)]
+		nn.Forward(images[firstIdx[d]])
+		out := nn.ExtractOutput()
+
+		ref, ok := refs[d]
+		if !ok {
+			refs[d] = out
+			continue
+		}
+		maxAbs, _ := driftMaxAndMAE(ref, out)
+		if maxAbs > res.MaxDrift {
+			res.MaxDrift = maxAbs
+		}
+		if maxAbs > stressEpsilon || len(out) != len(ref) {
+			res.Mismatches++
+			f := StressFailure{Iter: i, Digit: d, MaxAbs: maxAbs, PredChanged: argmax64(ref) != argmax64(out)}
+			if len(res.Failures) < maxStressFailures {
+				res.Failures = append(res.Failures, f)
+			}
+			if res.Mismatches <= 5 {
+				fmt.Printf("⚠️  iter %d digit %d: drift %.2e from reference%s\n",
+					f.Iter, f.Digit, f.MaxAbs, ternary(f.PredChanged, " (prediction changed!)", ""))
+			}
+		}
+		if (i+1)%1000 == 0 {
+			fmt.Printf("   … %d/%d iterations, %d mismatch(es)\n", i+1, iters, res.Mismatches)
+		}
+	}
+	res.ElapsedMS = float64(time.Since(start).Microseconds()) / 1000.0
+	recordTiming("eval", time.Since(start))
+	return res, nil
+}
+
+// runStressPrompt asks for iterations/device and runs stressModel on modelPath.
+func runStressPrompt(reader *bufio.Reader, modelPath string) {
+	iters := 5000
+	fmt.Printf("Iterations [default %d]: ", iters)
+	raw, _ := reader.ReadString('\n')
+	if v, err := strconv.Atoi(strings.TrimSpace(raw)); err == nil && v > 0 {
+		iters = v
+	}
+	fmt.Print("Device [gpu/cpu] (default gpu): ")
+	devRaw, _ := reader.ReadString('\n')
+	useGPU := strings.TrimSpace(strings.ToLower(devRaw)) != "cpu"
+
+	fmt.Printf("\n▶ Stressing %s: %d forwards on %s\n", modelPath, iters, ternary(useGPU, "GPU", "CPU"))
+	res, err := stressModel(modelPath, iters, useGPU)
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	if res.Mismatches == 0 {
+		fmt.Printf("✅ %d iterations, no drift above %.0e (max %.2e) in %.0fms\n",
+			res.Iters, res.Epsilon, res.MaxDrift, res.ElapsedMS)
+		return
+	}
+	fmt.Printf("❌ %d/%d iterations drifted above %.0e (max %.2e)\n",
+		res.Mismatches, res.Iters, res.Epsilon, res.MaxDrift)
+	fmt.Println(res.ToJSON())
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
+	"math"
 	"runtime"
 	"sort"
 	"strings"
@@ -16,9 +18,20 @@ type BenchInfo struct {
 	EndedAt       time.Time                          `json:"ended_at"`
 	DurationSec   float64                            `json:"duration_sec"`
 	NumCPU        int                                `json:"num_cpu"`
-	Filter        string                             `json:"filter"` // "all", "ints", "floats", or comma list (e.g., "int,float32")
+	Filter        string                             `json:"filter"`   // "all", "ints", "floats", or comma list (e.g., "int,float32")
+	RunMode       string                             `json:"run_mode"` // "sequential" (one paragon.RunAllBenchmarks pass) or "isolated" (one run per type)
 	Results       []paragon.BenchmarkResult          `json:"results"`
 	ResultsByType map[string]paragon.BenchmarkResult `json:"results_by_type,omitempty"`
+	System        SystemInfo                         `json:"system_info"` // machine the bench ran on
+
+	// Coverage: the types the filter asked for (nil for "all") and the ones
+	// among them that produced no result, e.g. a typo or "int128".
+	RequestedTypes []string `json:"requested_types,omitempty"`
+	MissingTypes   []string `json:"missing_types,omitempty"`
+
+	// Adaptive mode only: the precision target and how each type got there.
+	TargetRelErr float64       `json:"target_rel_err,omitempty"`
+	Adaptive     []AdaptiveRun `json:"adaptive,omitempty"`
 }
 
 func (b BenchInfo) ToJSON() string {
@@ -26,6 +39,80 @@ func (b BenchInfo) ToJSON() string {
 	return string(bz)
 }
 
+const (
+	BenchModeSequential = "sequential"
+	BenchModeIsolated   = "isolated"
+	BenchModeAdaptive   = "adaptive"
+)
+
+// --bench-rel-err is the precision adaptive runs aim for: a type keeps
+// running until the relative standard error of its throughput is below it.
+var flagBenchRelErr = flag.Float64("bench-rel-err", 0.02, "Target relative error for adaptive benchmark runs (0.02 = ±2%)")
+
+func benchRelErr() float64 {
+	if flag.Parsed() && *flagBenchRelErr > 0 {
+		return *flagBenchRelErr
+	}
+	return 0.02
+}
+
+// AdaptiveStat is how one adaptive measurement went.
+type AdaptiveStat struct {
+	OpsPerSec float64 `json:"ops_per_sec"`
+	Seconds   float64 `json:"seconds"`   // time actually spent measuring
+	Chunks    int     `json:"chunks"`    // short runs the estimate is built from
+	RelErr    float64 `json:"rel_err"`   // relative standard error of OpsPerSec
+	Converged bool    `json:"converged"` // RelErr reached the target before the max duration
+}
+
+// AdaptiveRun is the adaptive detail for one type.
+type AdaptiveRun struct {
+	Type   string       `json:"type"`
+	Single AdaptiveStat `json:"single"`
+	Multi  AdaptiveStat `json:"multi"`
+}
+
+// adaptiveMinChunks is how many chunks a type runs before its error
+// estimate is trusted.
+const adaptiveMinChunks = 3
+
+// benchAdaptive runs type t in short chunks until the relative standard
+// error of the throughput is at most target, or maxDur has been spent.
+func benchAdaptive(t string, multi bool, maxDur time.Duration, target float64) (AdaptiveStat, bool) {
+	chunk := max(maxDur/20, 50*time.Millisecond)
+	var rates []float64
+	var st AdaptiveStat
+	start := time.Now()
+	for time.Since(start) < maxDur {
+		cs := time.Now()
+		ops, ok := benchOps(t, chunk, multi)
+		if !ok {
+			return st, false
+		}
+		rates = append(rates, float64(ops)/time.Since(cs).Seconds())
+		if len(rates) >= adaptiveMinChunks {
+			mean, sd := meanStdDev(rates)
+			st.RelErr = safeDiv(sd/math.Sqrt(float64(len(rates))), mean)
+			if st.RelErr <= target {
+				st.Converged = true
+				break
+			}
+		}
+	}
+	st.OpsPerSec, _ = meanStdDev(rates)
+	st.Seconds = time.Since(start).Seconds()
+	st.Chunks = len(rates)
+	return st, true
+}
+
+// benchTypeOrder is the fixed sequence used by isolated runs (same order as
+// paragon.RunAllBenchmarks).
+var benchTypeOrder = []string{
+	"int", "int8", "int16", "int32", "int64",
+	"uint", "uint8", "uint16", "uint32", "uint64",
+	"float32", "float64",
+}
+
 // CollectBenchmarks runs the Paragon numeric micro-bench for `duration` and
 // returns structured results. `filter` can be:
 //   - "all" (default) to keep all numeric types
@@ -33,20 +120,73 @@ func (b BenchInfo) ToJSON() string {
 //   - "floats" to keep float32/float64 only
 //   - a comma list: e.g. "int,int32,float32"
 func CollectBenchmarks(duration time.Duration, filter string) (BenchInfo, error) {
+	return CollectBenchmarksMode(duration, filter, BenchModeSequential)
+}
+
+// CollectBenchmarksMode is CollectBenchmarks with an explicit run mode:
+//   - "sequential": one paragon.RunAllBenchmarks pass, then filter
+//   - "isolated":   each selected type runs on its own, in benchTypeOrder,
+//     with a GC + cooldown in between so cache/thermal state left by the
+//     previous type doesn't leak into the next one's numbers
+func CollectBenchmarksMode(duration time.Duration, filter, mode string) (BenchInfo, error) {
 	if filter == "" {
 		filter = "all"
 	}
-
-	start := time.Now()
-	raw := paragon.RunAllBenchmarks(duration) // returns JSON []BenchmarkResult
-	end := time.Now()
+	if mode == "" {
+		mode = BenchModeSequential
+	}
+	// Probe before the clock starts; cached, so repeated benches pay once.
+	sys := Collect()
 
 	var results []paragon.BenchmarkResult
-	if err := json.Unmarshal([]byte(raw), &results); err != nil {
-		return BenchInfo{}, fmt.Errorf("failed to parse paragon benchmarks: %w", err)
+	var adaptive []AdaptiveRun
+	start := time.Now()
+	switch mode {
+	case BenchModeSequential:
+		raw := paragon.RunAllBenchmarks(duration) // returns JSON []BenchmarkResult
+		if err := json.Unmarshal([]byte(raw), &results); err != nil {
+			return BenchInfo{}, fmt.Errorf("failed to parse paragon benchmarks: %w", err)
+		}
+		results = applyBenchFilter(results, filter)
+	case BenchModeIsolated:
+		keep := benchFilterSet(filter)
+		for _, t := range benchTypeOrder {
+			if len(keep) > 0 && !keep[t] {
+				continue
+			}
+			runtime.GC()
+			time.Sleep(500 * time.Millisecond)
+			if r, ok := benchOneType(t, duration); ok {
+				results = append(results, r)
+			}
+		}
+	case BenchModeAdaptive:
+		// duration is the per-type cap here; results are scaled to ops per
+		// duration so they read like the fixed-duration modes
+		keep := benchFilterSet(filter)
+		target := benchRelErr()
+		for _, t := range benchTypeOrder {
+			if len(keep) > 0 && !keep[t] {
+				continue
+			}
+			runtime.GC()
+			time.Sleep(500 * time.Millisecond)
+			single, ok := benchAdaptive(t, false, duration, target)
+			if !ok {
+				continue
+			}
+			multi, _ := benchAdaptive(t, true, duration, target)
+			results = append(results, paragon.BenchmarkResult{
+				Type:   t,
+				Single: int(single.OpsPerSec * duration.Seconds()),
+				Multi:  int(multi.OpsPerSec * duration.Seconds()),
+			})
+			adaptive = append(adaptive, AdaptiveRun{Type: t, Single: single, Multi: multi})
+		}
+	default:
+		return BenchInfo{}, fmt.Errorf("unknown bench run mode %q", mode)
 	}
-
-	results = applyBenchFilter(results, filter)
+	end := time.Now()
 
 	// Stable order by type name for deterministic logs
 	sort.Slice(results, func(i, j int) bool { return results[i].Type < results[j].Type })
@@ -62,16 +202,212 @@ func CollectBenchmarks(duration time.Duration, filter string) (BenchInfo, error)
 		DurationSec:   end.Sub(start).Seconds(),
 		NumCPU:        runtime.NumCPU(),
 		Filter:        filter,
+		RunMode:       mode,
 		Results:       results,
 		ResultsByType: byType,
+		System:        sys,
+		Adaptive:      adaptive,
+	}
+	if mode == BenchModeAdaptive {
+		info.TargetRelErr = benchRelErr()
 	}
+	for t := range benchFilterSet(filter) {
+		info.RequestedTypes = append(info.RequestedTypes, t)
+		if _, ok := byType[t]; !ok {
+			info.MissingTypes = append(info.MissingTypes, t)
+		}
+	}
+	sort.Strings(info.RequestedTypes)
+	sort.Strings(info.MissingTypes)
+	recordBenchToDB(info)
+	recordBenchHistory(info, duration)
 	return info, nil
 }
 
+// benchOneType runs paragon's single- and multi-threaded bench for one type.
+func benchOneType(t string, d time.Duration) (paragon.BenchmarkResult, bool) {
+	single, ok := benchOps(t, d, false)
+	if !ok {
+		return paragon.BenchmarkResult{}, false
+	}
+	multi, _ := benchOps(t, d, true)
+	return paragon.BenchmarkResult{Type: t, Single: single, Multi: multi}, true
+}
+
+// benchOps runs paragon.BenchmarkNumericOps for the type named t; ok is
+// false for names paragon has no type for.
+func benchOps(t string, d time.Duration, multi bool) (ops int, ok bool) {
+	switch t {
+	case "int":
+		return paragon.BenchmarkNumericOps[int](t, d, multi), true
+	case "int8":
+		return paragon.BenchmarkNumericOps[int8](t, d, multi), true
+	case "int16":
+		return paragon.BenchmarkNumericOps[int16](t, d, multi), true
+	case "int32":
+		return paragon.BenchmarkNumericOps[int32](t, d, multi), true
+	case "int64":
+		return paragon.BenchmarkNumericOps[int64](t, d, multi), true
+	case "uint":
+		return paragon.BenchmarkNumericOps[uint](t, d, multi), true
+	case "uint8":
+		return paragon.BenchmarkNumericOps[uint8](t, d, multi), true
+	case "uint16":
+		return paragon.BenchmarkNumericOps[uint16](t, d, multi), true
+	case "uint32":
+		return paragon.BenchmarkNumericOps[uint32](t, d, multi), true
+	case "uint64":
+		return paragon.BenchmarkNumericOps[uint64](t, d, multi), true
+	case "float32":
+		return paragon.BenchmarkNumericOps[float32](t, d, multi), true
+	case "float64":
+		return paragon.BenchmarkNumericOps[float64](t, d, multi), true
+	}
+	return 0, false
+}
+
+// benchSingleType benches one type `runs` times (multi-threaded) and returns
+// each run's throughput in ops/sec, for judging run-to-run variance.
+func benchSingleType(t string, duration time.Duration, runs int) ([]float64, error) {
+	return benchSingleTypeMode(t, duration, runs, true)
+}
+
+// benchSingleTypeMode is benchSingleType with a choice of single- or
+// multi-threaded ops. Runs are separated by a GC + cooldown like isolated mode.
+func benchSingleTypeMode(t string, duration time.Duration, runs int, multi bool) ([]float64, error) {
+	t = strings.ToLower(strings.TrimSpace(t))
+	if runs < 1 {
+		return nil, fmt.Errorf("runs must be ≥ 1")
+	}
+	if _, ok := benchOps(t, time.Millisecond, multi); !ok {
+		return nil, fmt.Errorf("unknown numeric type %q", t)
+	}
+	out := make([]float64, 0, runs)
+	for i := 0; i < runs; i++ {
+		runtime.GC()
+		time.Sleep(500 * time.Millisecond)
+		start := time.Now()
+		ops, _ := benchOps(t, duration, multi)
+		out = append(out, float64(ops)/time.Since(start).Seconds())
+		fmt.Printf("   run %d/%d: %s ops/s\n", i+1, runs, humanize(int(out[i])))
+	}
+	return out, nil
+}
+
+// ScalingPoint is one thread count on the scaling curve.
+type ScalingPoint struct {
+	Threads    int     `json:"threads"`
+	OpsPerSec  float64 `json:"ops_per_sec"`
+	Speedup    float64 `json:"speedup"`    // OpsPerSec / the 1-thread OpsPerSec
+	Efficiency float64 `json:"efficiency"` // Speedup / Threads; 1.0 is perfect scaling
+}
+
+// ScalingCurve is the thread-scaling result written to
+// public/analysis/thread_scaling.json.
+type ScalingCurve struct {
+	Type        string         `json:"type"`
+	DurationSec float64        `json:"duration_sec"` // per thread level
+	NumCPU      int            `json:"num_cpu"`
+	Points      []ScalingPoint `json:"points"`
+	System      SystemInfo     `json:"system_info"`
+	StartedAt   time.Time      `json:"started_at"`
+	EndedAt     time.Time      `json:"ended_at"`
+}
+
+func (c ScalingCurve) ToJSON() string {
+	bz, _ := json.MarshalIndent(c, "", "  ")
+	return string(bz)
+}
+
+// threadLevels is 1, 2, 4, … up to and always including max.
+func threadLevels(max int) []int {
+	var out []int
+	for n := 1; n < max; n *= 2 {
+		out = append(out, n)
+	}
+	return append(out, max)
+}
+
+// benchScaling runs the multi-threaded bench for type t with GOMAXPROCS
+// pinned to each of threadLevels(NumCPU) in turn, and reports throughput and
+// parallel efficiency per level. paragon starts one goroutine per CPU either
+// way; GOMAXPROCS caps how many of them run at once. The previous GOMAXPROCS
+// is restored afterwards.
+func benchScaling(t string, d time.Duration) (ScalingCurve, error) {
+	t = strings.ToLower(strings.TrimSpace(t))
+	if _, ok := benchOps(t, time.Millisecond, true); !ok {
+		return ScalingCurve{}, fmt.Errorf("unknown numeric type %q", t)
+	}
+	curve := ScalingCurve{
+		Type:        t,
+		DurationSec: d.Seconds(),
+		NumCPU:      runtime.NumCPU(),
+		System:      Collect(),
+		StartedAt:   time.Now().UTC(),
+	}
+	prev := runtime.GOMAXPROCS(0)
+	defer runtime.GOMAXPROCS(prev)
+
+	for _, n := range threadLevels(curve.NumCPU) {
+		runtime.GOMAXPROCS(n)
+		runtime.GC()
+		time.Sleep(500 * time.Millisecond)
+		start := time.Now()
+		ops, _ := benchOps(t, d, true)
+		pt := ScalingPoint{Threads: n, OpsPerSec: float64(ops) / time.Since(start).Seconds()}
+		if len(curve.Points) > 0 {
+			pt.Speedup = safeDiv(pt.OpsPerSec, curve.Points[0].OpsPerSec)
+		} else {
+			pt.Speedup = 1
+		}
+		pt.Efficiency = pt.Speedup / float64(n)
+		curve.Points = append(curve.Points, pt)
+		fmt.Printf("   %3d thread(s): %s ops/s | speedup %.2fx | efficiency %.0f%%\n",
+			n, humanize(int(pt.OpsPerSec)), pt.Speedup, pt.Efficiency*100)
+	}
+	curve.EndedAt = time.Now().UTC()
+	return curve, nil
+}
+
+// meanStdDev returns the mean and sample standard deviation of xs.
+func meanStdDev(xs []float64) (mean, sd float64) {
+	if len(xs) == 0 {
+		return 0, 0
+	}
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= float64(len(xs))
+	if len(xs) < 2 {
+		return mean, 0
+	}
+	for _, x := range xs {
+		sd += (x - mean) * (x - mean)
+	}
+	return mean, math.Sqrt(sd / float64(len(xs)-1))
+}
+
 func applyBenchFilter(rs []paragon.BenchmarkResult, filter string) []paragon.BenchmarkResult {
+	keep := benchFilterSet(filter)
+	if len(keep) == 0 {
+		return rs
+	}
+
+	out := make([]paragon.BenchmarkResult, 0, len(rs))
+	for _, r := range rs {
+		if keep[strings.ToLower(r.Type)] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// benchFilterSet expands a filter into the set of type names to keep.
+// An empty set means "keep everything".
+func benchFilterSet(filter string) map[string]bool {
 	filter = strings.ToLower(strings.TrimSpace(filter))
 	if filter == "" || filter == "all" {
-		return rs
+		return nil
 	}
 
 	var keep = map[string]bool{}
@@ -92,12 +428,5 @@ func applyBenchFilter(rs []paragon.BenchmarkResult, filter string) []paragon.Ben
 			}
 		}
 	}
-
-	out := make([]paragon.BenchmarkResult, 0, len(rs))
-	for _, r := range rs {
-		if len(keep) == 0 || keep[strings.ToLower(r.Type)] {
-			out = append(out, r)
-		}
-	}
-	return out
+	return keep
 }
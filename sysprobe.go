@@ -6,26 +6,36 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"os/exec"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/openfluke/paragon/v3"
 )
 
 type SystemInfo struct {
-	Architecture string              `json:"architecture"` // x86_64, arm64 (normalized)
-	OS           string              `json:"os"`           // linux, darwin, windows
-	OSVersion    string              `json:"os_version"`   // e.g., "Ubuntu 22.04", "macOS 14.6", "Windows 11 10.0.22631"
-	CPUModel     string              `json:"cpu_model"`
-	GPUModel     string              `json:"gpu_model"`
-	DeviceModel  string              `json:"device_model"` // laptop/desktop model where available
-	RAMBytes     uint64              `json:"ram_bytes"`
-	GPUs         []map[string]string `json:"gpus,omitempty"` // detailed WebGPU adapter info (if available)
+	Architecture     string              `json:"architecture"` // x86_64, arm64 (normalized)
+	OS               string              `json:"os"`           // linux, darwin, windows
+	OSVersion        string              `json:"os_version"`   // e.g., "Ubuntu 22.04", "macOS 14.6", "Windows 11 10.0.22631"
+	CPUModel         string              `json:"cpu_model"`
+	PhysicalCores    int                 `json:"physical_cores,omitempty"` // 0 (omitted) when unknown
+	LogicalCores     int                 `json:"logical_cores,omitempty"`  // hardware threads; runtime.NumCPU() when the probe fails
+	GPUModel         string              `json:"gpu_model"`
+	GPUDriverVersion string              `json:"gpu_driver_version,omitempty"` // e.g. "550.54.14", "Mesa 24.0.5"; best effort
+	GPUMemoryBytes   uint64              `json:"gpu_memory_bytes,omitempty"`   // VRAM of the largest GPU; 0 (omitted) when unknown or unified memory
+	DeviceModel      string              `json:"device_model"`                 // laptop/desktop model where available
+	Virtualization   string              `json:"virtualization,omitempty"`     // none, docker, podman, lxc, kubernetes, wsl, kvm, vmware, hyperv, ...; "" when unknown
+	RAMBytes         uint64              `json:"ram_bytes"`
+	GPUs             []map[string]string `json:"gpus,omitempty"`          // detailed WebGPU adapter info (if available)
+	WebGPUUsable     bool                `json:"webgpu_usable,omitempty"` // a real InitializeOptimizedGPU succeeded; authoritative over GPUs
+	WebGPUProbeError string              `json:"webgpu_probe_error,omitempty"`
+	Build            BuildInfo           `json:"build"` // binary that collected this info
 }
 
 func (s SystemInfo) ToJSON() string {
@@ -33,11 +43,39 @@ func (s SystemInfo) ToJSON() string {
 	return string(b)
 }
 
-// Collect probes the current machine with per-OS strategies.
+var (
+	sysInfoOnce   sync.Once
+	sysInfoCached SystemInfo
+)
+
+// Collect returns the machine's SystemInfo. Hardware is probed once per
+// process (a dozen external commands on some OSes); later calls return the
+// cached result. Each caller gets its own copy of the GPUs slice and maps,
+// so mutating the result can't corrupt the cache. Use CollectFresh to
+// re-probe.
 func Collect() SystemInfo {
+	sysInfoOnce.Do(func() { sysInfoCached = CollectFresh() })
+	info := sysInfoCached
+	if info.GPUs != nil {
+		info.GPUs = make([]map[string]string, len(sysInfoCached.GPUs))
+		for i, g := range sysInfoCached.GPUs {
+			m := make(map[string]string, len(g))
+			for k, v := range g {
+				m[k] = v
+			}
+			info.GPUs[i] = m
+		}
+	}
+	return info
+}
+
+// CollectFresh probes the current machine with per-OS strategies, bypassing
+// Collect's cache.
+func CollectFresh() SystemInfo {
 	info := SystemInfo{
 		Architecture: normalizeArch(runtime.GOARCH),
 		OS:           runtime.GOOS,
+		Build:        currentBuild(),
 	}
 
 	// Best-effort WebGPU adapter enumeration (non-fatal if it fails)
@@ -107,14 +145,413 @@ func Collect() SystemInfo {
 		info.DeviceModel = ""
 	}
 
+	info.Virtualization = probeVirtualization(info.DeviceModel)
+	info.PhysicalCores, info.LogicalCores = probeCPUCores()
+	if info.LogicalCores <= 0 {
+		info.LogicalCores = runtime.NumCPU()
+	}
+
+	info.GPUDriverVersion = probeGPUDriverVersion(info.GPUs)
+	vram := probeGPUMemory()
+	for _, v := range vram {
+		info.GPUMemoryBytes = max(info.GPUMemoryBytes, v.bytes)
+	}
+	attachVRAM(info.GPUs, vram)
+
 	// Final cleanup/normalization
 	info.CPUModel = compactOneLine(info.CPUModel)
 	info.GPUModel = compactOneLine(info.GPUModel)
 	info.DeviceModel = compactOneLine(info.DeviceModel)
+	info.GPUDriverVersion = compactOneLine(info.GPUDriverVersion)
 
 	return info
 }
 
+// probeGPUDriverVersion returns the GPU driver version — the usual explanation
+// when identical GPUs drift differently. Falls back to the WebGPU adapter's
+// driverDescription when no OS tool answers.
+func probeGPUDriverVersion(gpus []map[string]string) string {
+	var v string
+	switch runtime.GOOS {
+	case "linux":
+		v = firstNonEmpty(
+			firstLineClean(runOne("nvidia-smi", "--query-gpu=driver_version", "--format=csv,noheader")),
+			runOne("bash", "-lc", `glxinfo -B 2>/dev/null | awk -F: '/OpenGL version string/{sub(/^[ \t]+/,"",$2);print $2; exit}'`),
+			runOne("bash", "-lc", `modinfo -F version amdgpu 2>/dev/null`),
+		)
+	case "darwin":
+		// GPU drivers ship with macOS; the Metal family is the closest per-GPU stamp.
+		v = runOne("bash", "-lc", `system_profiler SPDisplaysDataType | awk -F: '/Metal (Support|Family)/{sub(/^[ \t]+/,"",$2);print $2; exit}'`)
+	case "windows":
+		v = firstNonEmpty(
+			firstLineClean(runOne("wmic", "path", "win32_VideoController", "get", "DriverVersion")),
+			firstLineClean(runOne("powershell", "-NoProfile", "Get-CimInstance Win32_VideoController | Select-Object -ExpandProperty DriverVersion")),
+		)
+	}
+	if strings.TrimSpace(v) == "" {
+		for _, g := range gpus {
+			if d := strings.TrimSpace(g["driverDescription"]); d != "" {
+				return d
+			}
+		}
+	}
+	return v
+}
+
+// probeVirtualization names the container or hypervisor the process runs
+// in, "none" on bare metal. A container wins over the VM underneath it: it
+// is what makes the numbers differ from the host's. On Linux that's WSL
+// (/proc/version), container markers (/.dockerenv, /run/.containerenv,
+// /proc/1/cgroup), systemd-detect-virt, then the DMI vendor and the cpuinfo
+// hypervisor flag; elsewhere only deviceModel (the vendor/model strings
+// Collect already gathered) is consulted.
+func probeVirtualization(deviceModel string) string {
+	if runtime.GOOS != "linux" {
+		if v := virtFromVendor(deviceModel); v != "" {
+			return v
+		}
+		return "none"
+	}
+
+	if strings.Contains(strings.ToLower(readFile("/proc/version")), "microsoft") {
+		return "wsl"
+	}
+	if _, err := os.Stat("/.dockerenv"); err == nil {
+		return "docker"
+	}
+	if _, err := os.Stat("/run/.containerenv"); err == nil {
+		return "podman"
+	}
+	if v := parseCgroupContainer(readFile("/proc/1/cgroup")); v != "" {
+		return v
+	}
+	// On bare metal it prints "none" but exits 1, so runOne returns "" and
+	// the checks below end at "none" too.
+	switch v := runOne("systemd-detect-virt"); v {
+	case "":
+	case "microsoft":
+		return "hyperv"
+	case "oracle":
+		return "virtualbox"
+	default:
+		return v // none, kvm, qemu, vmware, xen, wsl, docker, lxc, ...
+	}
+	if v := virtFromVendor(deviceModel); v != "" {
+		return v
+	}
+	if cpuinfo := readFile("/proc/cpuinfo"); cpuinfo != "" {
+		for _, line := range strings.Split(cpuinfo, "\n") {
+			if k, flags, ok := strings.Cut(line, ":"); ok && strings.TrimSpace(k) == "flags" {
+				if strings.Contains(" "+flags+" ", " hypervisor ") {
+					return "vm" // some hypervisor, but none of the above says which
+				}
+				break
+			}
+		}
+	}
+	return "none"
+}
+
+// parseCgroupContainer spots a container runtime in a /proc/1/cgroup dump
+// (cgroup v1 paths such as "/docker/<id>"; v2 is usually just "0::/").
+func parseCgroupContainer(cgroup string) string {
+	s := strings.ToLower(cgroup)
+	switch {
+	case strings.Contains(s, "kubepods"):
+		return "kubernetes"
+	case strings.Contains(s, "docker"):
+		return "docker"
+	case strings.Contains(s, "libpod"):
+		return "podman"
+	case strings.Contains(s, "/lxc"):
+		return "lxc"
+	}
+	return ""
+}
+
+// virtFromVendor maps a DMI/WMI vendor+model or macOS hw.model string to a
+// hypervisor name, "" when it looks like real hardware.
+func virtFromVendor(s string) string {
+	s = strings.ToLower(s)
+	switch {
+	case strings.Contains(s, "vmware"):
+		return "vmware"
+	case strings.Contains(s, "virtualbox"):
+		return "virtualbox"
+	case strings.Contains(s, "parallels"):
+		return "parallels"
+	case strings.Contains(s, "microsoft") && strings.Contains(s, "virtual"):
+		return "hyperv"
+	case strings.Contains(s, "qemu"), strings.Contains(s, "kvm"):
+		return "kvm"
+	case strings.Contains(s, "xen"):
+		return "xen"
+	case strings.HasPrefix(s, "virtualmac"):
+		return "apple-vz"
+	}
+	return ""
+}
+
+// probeCPUCores returns the physical core and logical processor counts,
+// 0 for whichever the OS tools don't answer.
+func probeCPUCores() (physical, logical int) {
+	switch runtime.GOOS {
+	case "linux":
+		physical, logical = parseCPUInfoCores(readFile("/proc/cpuinfo"))
+		if physical == 0 {
+			physical = parseLscpuCores(runOne("lscpu", "-p=Core,Socket"))
+		}
+	case "darwin":
+		physical = int(parseUint(runOne("sysctl", "-n", "hw.physicalcpu")))
+		logical = int(parseUint(runOne("sysctl", "-n", "hw.logicalcpu")))
+	case "windows":
+		out := firstNonEmpty(
+			runOne("wmic", "cpu", "get", "NumberOfCores,NumberOfLogicalProcessors", "/Value"),
+			runOne("powershell", "-NoProfile", `Get-CimInstance Win32_Processor | ForEach-Object { "NumberOfCores=$($_.NumberOfCores)"; "NumberOfLogicalProcessors=$($_.NumberOfLogicalProcessors)" }`),
+		)
+		// one pair per socket
+		for _, line := range strings.Split(out, "\n") {
+			k, v, ok := strings.Cut(strings.TrimSpace(line), "=")
+			if !ok {
+				continue
+			}
+			switch k {
+			case "NumberOfCores":
+				physical += int(parseUint(v))
+			case "NumberOfLogicalProcessors":
+				logical += int(parseUint(v))
+			}
+		}
+	}
+	return physical, logical
+}
+
+// parseCPUInfoCores counts processors and distinct (physical id, core id)
+// pairs in /proc/cpuinfo. Physical is 0 when the file has no core ids (many
+// ARM kernels).
+func parseCPUInfoCores(cpuinfo string) (physical, logical int) {
+	cores := map[string]bool{}
+	var pkg, core string
+	flush := func() {
+		if core != "" {
+			cores[pkg+"/"+core] = true
+		}
+		pkg, core = "", ""
+	}
+	for _, line := range strings.Split(cpuinfo, "\n") {
+		k, v, ok := strings.Cut(line, ":")
+		if !ok {
+			if strings.TrimSpace(line) == "" {
+				flush()
+			}
+			continue
+		}
+		switch strings.TrimSpace(k) {
+		case "processor":
+			logical++
+		case "physical id":
+			pkg = strings.TrimSpace(v)
+		case "core id":
+			core = strings.TrimSpace(v)
+		}
+	}
+	flush()
+	return len(cores), logical
+}
+
+// parseLscpuCores counts distinct core,socket pairs in `lscpu -p=Core,Socket`.
+func parseLscpuCores(out string) int {
+	cores := map[string]bool{}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		cores[line] = true
+	}
+	return len(cores)
+}
+
+// gpuVRAM is one adapter's dedicated memory as an OS tool reports it.
+type gpuVRAM struct {
+	name  string
+	bytes uint64
+}
+
+// probeGPUMemory lists the dedicated memory of each GPU the OS tools know
+// about: nvidia-smi (Linux/Windows), then the largest prefetchable BAR from
+// lspci -v on Linux, system_profiler on macOS, Win32_VideoController's
+// AdapterRAM on Windows (a 32-bit field, so it tops out at 4 GiB there).
+func probeGPUMemory() []gpuVRAM {
+	if out := parseNvidiaSMIMemory(runOne("nvidia-smi", "--query-gpu=name,memory.total", "--format=csv,noheader,nounits")); len(out) > 0 {
+		return out
+	}
+	switch runtime.GOOS {
+	case "linux":
+		return parseLspciVRAM(runOne("lspci", "-v"))
+	case "darwin":
+		return parseSystemProfilerVRAM(runOne("system_profiler", "SPDisplaysDataType"))
+	case "windows":
+		return parseAdapterRAM(firstNonEmpty(
+			runOne("wmic", "path", "win32_VideoController", "get", "AdapterRAM,Name", "/Value"),
+			runOne("powershell", "-NoProfile", `Get-CimInstance Win32_VideoController | ForEach-Object { "AdapterRAM=$($_.AdapterRAM)"; "Name=$($_.Name)" }`),
+		))
+	}
+	return nil
+}
+
+// parseNvidiaSMIMemory parses "name, MiB" lines.
+func parseNvidiaSMIMemory(out string) []gpuVRAM {
+	var vs []gpuVRAM
+	for _, line := range strings.Split(out, "\n") {
+		name, mib, ok := strings.Cut(line, ",")
+		if n := parseUint(mib); ok && n > 0 {
+			vs = append(vs, gpuVRAM{name: strings.TrimSpace(name), bytes: n << 20})
+		}
+	}
+	return vs
+}
+
+// parseLspciVRAM takes, for each display controller in `lspci -v`, its
+// largest prefetchable memory region. Without resizable BAR that can be a
+// 256M window rather than the full VRAM, which is why it is only a fallback.
+func parseLspciVRAM(out string) []gpuVRAM {
+	var vs []gpuVRAM
+	for _, block := range strings.Split(out, "\n\n") {
+		lines := strings.Split(strings.TrimSpace(block), "\n")
+		head := strings.ToLower(lines[0])
+		if !strings.Contains(head, "vga") && !strings.Contains(head, "3d") && !strings.Contains(head, "display") {
+			continue
+		}
+		var best uint64
+		for _, l := range lines[1:] {
+			l = strings.TrimSpace(l)
+			if !strings.HasPrefix(l, "Memory at") || !strings.Contains(l, "prefetchable") || strings.Contains(l, "non-prefetchable") {
+				continue
+			}
+			if i := strings.Index(l, "[size="); i >= 0 {
+				best = max(best, parseSizeSuffix(strings.TrimSuffix(l[i+len("[size="):], "]")))
+			}
+		}
+		if best == 0 {
+			continue
+		}
+		name := lines[0]
+		if _, after, ok := strings.Cut(name, ": "); ok {
+			name = after
+		}
+		if i := strings.Index(name, " (rev "); i >= 0 {
+			name = name[:i]
+		}
+		vs = append(vs, gpuVRAM{name: strings.TrimSpace(name), bytes: best})
+	}
+	return vs
+}
+
+// parseSizeSuffix parses lspci sizes like "256M" or "16G".
+func parseSizeSuffix(s string) uint64 {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	mult := map[byte]uint64{'K': 1 << 10, 'M': 1 << 20, 'G': 1 << 30, 'T': 1 << 40}[s[len(s)-1]]
+	if mult == 0 {
+		return parseUint(s)
+	}
+	return parseUint(s[:len(s)-1]) * mult
+}
+
+// parseSystemProfilerVRAM pairs each "Chipset Model:" with the "VRAM (…):"
+// line that follows it ("8 GB", "1536 MB"). Apple silicon has no VRAM line:
+// its memory is unified, reported as 0.
+func parseSystemProfilerVRAM(out string) []gpuVRAM {
+	var vs []gpuVRAM
+	for _, line := range strings.Split(out, "\n") {
+		k, v, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		switch {
+		case k == "Chipset Model":
+			vs = append(vs, gpuVRAM{name: strings.TrimSpace(v)})
+		case strings.HasPrefix(k, "VRAM") && len(vs) > 0:
+			f := strings.Fields(v)
+			if len(f) == 2 {
+				n := parseUint(f[0])
+				switch strings.ToUpper(f[1]) {
+				case "GB":
+					n <<= 30
+				case "MB":
+					n <<= 20
+				}
+				vs[len(vs)-1].bytes = n
+			}
+		}
+	}
+	kept := vs[:0]
+	for _, v := range vs {
+		if v.bytes > 0 {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// parseAdapterRAM parses "AdapterRAM=…" / "Name=…" pairs, one per adapter.
+func parseAdapterRAM(out string) []gpuVRAM {
+	var vs []gpuVRAM
+	var ram uint64
+	for _, line := range strings.Split(out, "\n") {
+		k, v, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "AdapterRAM":
+			ram = parseUint(v)
+		case "Name":
+			if ram > 0 {
+				vs = append(vs, gpuVRAM{name: strings.TrimSpace(v), bytes: ram})
+			}
+			ram = 0
+		}
+	}
+	return vs
+}
+
+// attachVRAM sets "vram_bytes" on the WebGPU adapters in gpus. Adapters are
+// matched to the OS tool's list by name; when names don't line up (lspci
+// names chips, WebGPU names products) a single probed GPU is attached to
+// the adapters if they all share one device ID.
+func attachVRAM(gpus []map[string]string, vram []gpuVRAM) {
+	if len(gpus) == 0 || len(vram) == 0 {
+		return
+	}
+	matched := false
+	for _, g := range gpus {
+		name := strings.ToLower(g["name"])
+		for _, v := range vram {
+			vn := strings.ToLower(v.name)
+			if name != "" && vn != "" && (strings.Contains(name, vn) || strings.Contains(vn, name)) {
+				g["vram_bytes"] = strconv.FormatUint(v.bytes, 10)
+				matched = true
+				break
+			}
+		}
+	}
+	if matched || len(vram) != 1 {
+		return
+	}
+	for _, g := range gpus[1:] {
+		if g["deviceId"] != gpus[0]["deviceId"] {
+			return
+		}
+	}
+	for _, g := range gpus {
+		g["vram_bytes"] = strconv.FormatUint(vram[0].bytes, 10)
+	}
+}
+
 // ---------- helpers ----------
 
 func normalizeArch(goarch string) string {
@@ -158,11 +595,36 @@ func compactOneLine(s string) string {
 	return strings.TrimSpace(s)
 }
 
-func runOne(name string, args ...string) string {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+// probeTimeout bounds each external command of the hardware probe. Some
+// machines need longer: system_profiler and glxinfo can take 3–5s, and a probe
+// that times out just leaves its field empty.
+var (
+	probeTimeout     = 2 * time.Second
+	flagProbeTimeout = flag.Duration("probe-timeout", 2*time.Second, "Timeout for each external command of the hardware probe (lspci, glxinfo, system_profiler, wmic, ...)")
+)
+
+// SetProbeTimeout sets the per-command probe timeout; d ≤ 0 is ignored. Call
+// it before the first Collect, which caches its result.
+func SetProbeTimeout(d time.Duration) {
+	if d > 0 {
+		probeTimeout = d
+	}
+}
+
+// runOne runs one probe command. It is a variable so the probe can be run
+// against stub commands.
+var runOne = runOneExec
+
+// runOneExec runs one probe command and returns its trimmed output, or ""
+// if it fails or outlives probeTimeout.
+func runOneExec(name string, args ...string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
 	defer cancel()
 	cmd := exec.CommandContext(ctx, name, args...)
 	cmd.Env = os.Environ()
+	// A killed `bash -lc` can leave pipeline children holding the output
+	// pipe open; don't wait on them past the deadline.
+	cmd.WaitDelay = 500 * time.Millisecond
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return ""
@@ -308,3 +770,62 @@ func parseUint(s string) uint64 {
 
 // ---- Optional: error helpers (not used heavily here) ----
 var errNotFound = errors.New("not found")
+
+var (
+	webGPUProbeOnce sync.Once
+	webGPUProbeOK   bool
+	webGPUProbeErr  string
+)
+
+// webGPUUsable reports whether WebGPU actually initializes on this machine,
+// by running InitializeOptimizedGPU on a tiny network once per process. This
+// is the answer to "is there a GPU", not adapter enumeration, and every GPU
+// path checks it (via requireWebGPU) before trying its own init.
+func webGPUUsable() (bool, string) {
+	if cpuOnly() {
+		return false, "skipped (--cpu-only)"
+	}
+	webGPUProbeOnce.Do(func() {
+		defer func() {
+			// Enumeration and usability disagree on some boxes (software
+			// adapters on headless machines enumerate nothing but init fine).
+			switch adapters := len(Collect().GPUs); {
+			case webGPUProbeOK && adapters == 0:
+				fmt.Println("ℹ️  No WebGPU adapters enumerated, but GPU init works (likely a software/fallback adapter) — GPU paths stay enabled")
+			case !webGPUProbeOK && adapters > 0:
+				fmt.Printf("⚠️  %d WebGPU adapter(s) enumerated, but GPU init fails: %s\n", adapters, webGPUProbeErr)
+			}
+		}()
+		nn, err := buildSpecNetwork(ModelSpec{Layers: []string{"784", "8", "10"}})
+		if err != nil {
+			webGPUProbeErr = err.Error()
+			return
+		}
+		nn.WebGPUNative, nn.Debug = true, false
+		if err := nn.InitializeOptimizedGPU(); err != nil {
+			webGPUProbeErr = err.Error()
+			return
+		}
+		nn.CleanupOptimizedGPU()
+		webGPUProbeOK = true
+	})
+	return webGPUProbeOK, webGPUProbeErr
+}
+
+// requireWebGPU returns ErrGPUInitFailed with the probe's reason when
+// webGPUUsable found WebGPU unusable, so GPU paths fall back to the CPU
+// without each paying for a failing init.
+func requireWebGPU() error {
+	if ok, why := webGPUUsable(); !ok {
+		return fmt.Errorf("%w: %s", ErrGPUInitFailed, why)
+	}
+	return nil
+}
+
+// withWebGPUProbe fills in info's WebGPUUsable/WebGPUProbeError from the
+// cached webGPUUsable probe. Collect leaves them empty so a plain hardware
+// probe never initializes a device.
+func withWebGPUProbe(info SystemInfo) SystemInfo {
+	info.WebGPUUsable, info.WebGPUProbeError = webGPUUsable()
+	return info
+}
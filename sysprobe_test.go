@@ -0,0 +1,120 @@
+package main
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// resetCollectCache empties Collect's cache for the test and again after it.
+func resetCollectCache(t *testing.T) {
+	t.Helper()
+	sysInfoOnce, sysInfoCached = sync.Once{}, SystemInfo{}
+	t.Cleanup(func() { sysInfoOnce, sysInfoCached = sync.Once{}, SystemInfo{} })
+}
+
+func TestCollectProbesOnce(t *testing.T) {
+	resetCollectCache(t)
+	var mu sync.Mutex
+	calls := 0
+	orig := runOne
+	runOne = func(name string, args ...string) string {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return ""
+	}
+	t.Cleanup(func() { runOne = orig })
+
+	Collect()
+	first := calls
+	if first == 0 {
+		t.Fatal("the probe ran no external commands through runOne")
+	}
+	Collect()
+	if calls != first {
+		t.Fatalf("second Collect ran %d more external commands", calls-first)
+	}
+}
+
+func TestCollectReturnsCopies(t *testing.T) {
+	resetCollectCache(t)
+	sysInfoOnce.Do(func() {
+		sysInfoCached = SystemInfo{CPUModel: "cpu", GPUs: []map[string]string{{"name": "gpu0"}}}
+	})
+
+	a := Collect()
+	a.GPUs[0]["name"] = "mutated"
+	a.GPUs[0]["extra"] = "x"
+	a.GPUs = append(a.GPUs, map[string]string{"name": "gpu1"})
+
+	b := Collect()
+	if len(b.GPUs) != 1 || b.GPUs[0]["name"] != "gpu0" || len(b.GPUs[0]) != 1 {
+		t.Fatalf("cached GPUs changed through a caller's copy: %v", b.GPUs)
+	}
+}
+
+func TestRunOneTimeout(t *testing.T) {
+	orig := probeTimeout
+	t.Cleanup(func() { probeTimeout = orig })
+
+	SetProbeTimeout(100 * time.Millisecond)
+	start := time.Now()
+	if out := runOne("sleep", "1"); out != "" {
+		t.Fatalf("timed-out command returned %q", out)
+	}
+	if d := time.Since(start); d > 700*time.Millisecond {
+		t.Fatalf("runOne took %v with a 100ms timeout", d)
+	}
+
+	SetProbeTimeout(2 * time.Second)
+	if out := runOne("sh", "-c", "sleep 0.2; echo ok"); out != "ok" {
+		t.Fatalf("runOne with a 2s timeout = %q, want \"ok\"", out)
+	}
+}
+
+// TestMachineIDIgnoresVolatileFields checks fields that can change on the
+// same machine (or were added after IDs were issued) don't move its ID.
+func TestMachineIDIgnoresVolatileFields(t *testing.T) {
+	base := SystemInfo{Architecture: "x86_64", OS: "linux", CPUModel: "cpu", GPUModel: "gpu", RAMBytes: 1 << 34}
+	want := hashSystemInfo(base)
+
+	si := base
+	si.Build = BuildInfo{Version: "v9", Commit: "abc"}
+	if got := hashSystemInfo(si); got != want {
+		t.Fatalf("the build stamp changed the machine ID: %s → %s", want, got)
+	}
+
+	si = base
+	si.PhysicalCores, si.LogicalCores = 8, 16
+	if got := hashSystemInfo(si); got != want {
+		t.Fatalf("core counts changed the machine ID: %s → %s", want, got)
+	}
+
+	// A machine known before these fields existed keeps its ID.
+	old, _ := json.Marshal(struct {
+		Architecture string `json:"architecture"`
+		OS           string `json:"os"`
+		OSVersion    string `json:"os_version"`
+		CPUModel     string `json:"cpu_model"`
+		GPUModel     string `json:"gpu_model"`
+		DeviceModel  string `json:"device_model"`
+		RAMBytes     uint64 `json:"ram_bytes"`
+	}{base.Architecture, base.OS, base.OSVersion, base.CPUModel, base.GPUModel, base.DeviceModel, base.RAMBytes})
+	if sum := md5.Sum(old); hex.EncodeToString(sum[:]) != want {
+		t.Fatalf("machine ID %s differs from the pre-existing hash %x", want, sum)
+	}
+
+	// Zeroed fields must also drop out of the hashed JSON, or their keys
+	// alone change every existing ID.
+	b, _ := json.Marshal(SystemInfo{})
+	for _, k := range []string{"physical_cores", "logical_cores", "gpu_memory_bytes", "webgpu_usable", "virtualization"} {
+		if strings.Contains(string(b), `"`+k+`"`) {
+			t.Errorf("zero SystemInfo still marshals %q", k)
+		}
+	}
+}
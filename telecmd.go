@@ -2,9 +2,13 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 func runTelemetryMenu() {
@@ -12,11 +16,31 @@ func runTelemetryMenu() {
 
 	fmt.Print("Target host base (e.g., http://192.168.1.20:8080): ")
 	raw, _ := reader.ReadString('\n')
-	host := strings.TrimSpace(raw)
-	if host == "" {
-		fmt.Println("❌ host required")
+	host, err := normalizeHostBase(raw)
+	if err != nil {
+		fmt.Println("❌", err)
 		return
 	}
+	if host != strings.TrimSpace(raw) {
+		fmt.Println("↪ Using", host)
+	}
+
+	fmt.Print("Probe link speed first? [y/N]: ")
+	rawP, _ := reader.ReadString('\n')
+	if strings.EqualFold(strings.TrimSpace(rawP), "y") {
+		probe, err := probeHost(host)
+		if err != nil {
+			fmt.Println("⚠️  Probe failed:", err)
+		} else {
+			probe.Print()
+		}
+		fmt.Print("Continue with telemetry? [Y/n]: ")
+		rawC, _ := reader.ReadString('\n')
+		if strings.EqualFold(strings.TrimSpace(rawC), "n") {
+			fmt.Println("↩️  Cancelled")
+			return
+		}
+	}
 
 	fmt.Println("Source environment:")
 	fmt.Println(" 1) native")
@@ -34,8 +58,48 @@ func runTelemetryMenu() {
 		src = SourceWASMIonic
 	}
 
-	fmt.Printf("▶ Running telemetry against %s as %s…\n", host, src)
-	path, err := RunTelemetryPipeline(host, src)
+	var opts TelemetryOptions
+	fmt.Println("Sample selection:")
+	fmt.Println(" 1) first index per digit (classic protocol)")
+	fmt.Println(" 2) balanced random draw per digit (seeded)")
+	fmt.Print("Select [1-2] (default 1): ")
+	rawM, _ := reader.ReadString('\n')
+	if strings.TrimSpace(rawM) == "2" {
+		opts.Balanced = true
+		opts.SampleSeed = 42
+		fmt.Printf("Seed [default %d]: ", opts.SampleSeed)
+		rawSeed, _ := reader.ReadString('\n')
+		if v, err := strconv.ParseInt(strings.TrimSpace(rawSeed), 10, 64); err == nil {
+			opts.SampleSeed = v
+		}
+	}
+
+	fmt.Print("Delete downloaded models after a successful run? [y/N]: ")
+	rawCl, _ := reader.ReadString('\n')
+	opts.Cleanup = strings.EqualFold(strings.TrimSpace(rawCl), "y")
+
+	opts.CPUOnly = cpuOnly()
+	if !opts.CPUOnly {
+		fmt.Print("CPU-only baseline (skip WebGPU, both paths on CPU)? [y/N]: ")
+		rawCPU, _ := reader.ReadString('\n')
+		opts.CPUOnly = strings.EqualFold(strings.TrimSpace(rawCPU), "y")
+	}
+
+	fmt.Print("Also write per-sample timings as CSV? [y/N]: ")
+	rawCSV, _ := reader.ReadString('\n')
+	opts.CSV = strings.EqualFold(strings.TrimSpace(rawCSV), "y")
+
+	fmt.Print("Also write Prometheus metrics (.prom)? [y/N]: ")
+	rawProm, _ := reader.ReadString('\n')
+	opts.Prometheus = strings.EqualFold(strings.TrimSpace(rawProm), "y")
+
+	fmt.Printf("▶ Running telemetry against %s as %s%s…\n", host, src, ternary(opts.CPUOnly, " (CPU-only baseline)", ""))
+	path, err := RunTelemetryPipeline(host, src, opts)
+	if errors.Is(err, ErrUploadFailed) {
+		fmt.Println("❌ Upload failed:", err)
+		fmt.Printf("💾 Report retained locally at %s — retry with option %s (re-upload pending reports).\n", path, reuploadCommandCode)
+		return
+	}
 	if err != nil {
 		fmt.Println("❌ Telemetry failed:", err)
 		return
@@ -44,3 +108,99 @@ func runTelemetryMenu() {
 	fmt.Printf("📤 Uploaded report back to %s at /reports/\n", host)
 	fmt.Println("   Tip: Open ", host, "/reports/ to see it.")
 }
+
+// reuploadCommandCode is the menu code of runReuploadMenu, quoted in the
+// "upload failed" hint.
+const reuploadCommandCode = "19"
+
+// runReuploadMenu pushes the reports in public/reports_local that have no
+// upload marker yet to a host, for runs whose upload failed.
+func runReuploadMenu() {
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Print("Target host base (e.g., http://192.168.1.20:8080): ")
+	raw, _ := reader.ReadString('\n')
+	host, err := normalizeHostBase(raw)
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	if host != strings.TrimSpace(raw) {
+		fmt.Println("↪ Using", host)
+	}
+
+	uploaded, failed, err := reuploadPending(host)
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	if len(uploaded) == 0 && len(failed) == 0 {
+		fmt.Println("✅ No pending reports — everything in reports_local has been uploaded.")
+		return
+	}
+	fmt.Printf("✅ Re-upload done: %d sent, %d failed\n", len(uploaded), len(failed))
+	for _, name := range failed {
+		fmt.Printf("   still pending: %s\n", name)
+	}
+}
+
+// uploadedMarkerSuffix marks a local report as delivered: after a confirmed
+// upload of reports_local/<name>.json, <name>.json.uploaded is written next
+// to it (holding the host and time).
+const uploadedMarkerSuffix = ".uploaded"
+
+// markUploaded records that the report at path reached host.
+func markUploaded(path, host string) error {
+	line := fmt.Sprintf("%s %s\n", time.Now().UTC().Format(time.RFC3339), host)
+	return os.WriteFile(path+uploadedMarkerSuffix, []byte(line), 0o644)
+}
+
+// pendingReports lists the reports in dir that have no upload marker.
+func pendingReports(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") || isRunContextFile(e.Name()) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, e.Name()+uploadedMarkerSuffix)); err == nil {
+			continue
+		}
+		out = append(out, e.Name())
+	}
+	return out, nil
+}
+
+// reuploadPending pushes every local report without an upload marker to
+// hostBase and marks the ones that get through. Hosts drop reports they
+// already have, so a report whose marker was lost is not stored twice.
+func reuploadPending(hostBase string) (uploaded, failed []string, err error) {
+	if hostBase, err = normalizeHostBase(hostBase); err != nil {
+		return nil, nil, err
+	}
+	dir := MustPublicPath("reports_local")
+	pending, err := pendingReports(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	for _, name := range pending {
+		path := filepath.Join(dir, name)
+		fmt.Printf("📤 %s\n", name)
+		if err := uploadWithRetry(hostBase, path, name); err != nil {
+			fmt.Printf("❌ %s: %v\n", name, err)
+			failed = append(failed, name)
+			continue
+		}
+		if err := markUploaded(path, hostBase); err != nil {
+			fmt.Printf("⚠️  %s uploaded but could not be marked: %v\n", name, err)
+		}
+		uploaded = append(uploaded, name)
+	}
+	return uploaded, failed, nil
+}
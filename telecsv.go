@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// telemetryCSVHeader is the column order of telemetryToCSV.
+var telemetryCSVHeader = []string{"model", "digit", "idx", "cpu_ms", "gpu_ms", "pred_cpu", "pred_gpu", "max_abs", "mae"}
+
+// telemetryToCSV flattens a report to one row per model and digit, for pivot
+// tables. GPU timings and drift are matched to the CPU row by digit; a digit
+// missing on one side leaves those columns empty.
+func telemetryToCSV(report TelemetryReport) string {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write(telemetryCSVHeader)
+
+	ms := func(v float64) string { return strconv.FormatFloat(v, 'f', 6, 64) }
+	for _, mr := range report.PerModel {
+		gpu := make(map[int]SampleTiming, len(mr.GPU))
+		for _, t := range mr.GPU {
+			gpu[t.Digit] = t
+		}
+		drift := make(map[int]DriftMetrics, len(mr.Drift))
+		for _, d := range mr.Drift {
+			drift[d.Digit] = d
+		}
+		for _, c := range mr.CPU {
+			row := []string{mr.ModelFile, strconv.Itoa(c.Digit), strconv.Itoa(c.Idx), ms(c.ElapsedMS), "", strconv.Itoa(c.Pred), "", "", ""}
+			if g, ok := gpu[c.Digit]; ok {
+				row[4], row[6] = ms(g.ElapsedMS), strconv.Itoa(g.Pred)
+			}
+			if d, ok := drift[c.Digit]; ok {
+				row[7] = strconv.FormatFloat(d.MaxAbs, 'e', 6, 64)
+				row[8] = strconv.FormatFloat(d.MAE, 'e', 6, 64)
+			}
+			_ = w.Write(row)
+		}
+	}
+	w.Flush()
+	return buf.String()
+}
+
+// writeTelemetryCSV writes telemetryToCSV(report) next to the report at
+// jsonPath (same name, .csv) and returns the CSV path.
+func writeTelemetryCSV(jsonPath string, report TelemetryReport) (string, error) {
+	csvPath := strings.TrimSuffix(jsonPath, filepath.Ext(jsonPath)) + ".csv"
+	if err := os.WriteFile(csvPath, []byte(telemetryToCSV(report)), 0o644); err != nil {
+		return "", err
+	}
+	return csvPath, nil
+}
+
+// convertReportToCSV reads an existing telemetry report and writes its CSV.
+func convertReportToCSV(path string) (string, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	var report TelemetryReport
+	if err := json.Unmarshal(bz, &report); err != nil {
+		return "", fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+	}
+	return writeTelemetryCSV(path, report)
+}
+
+// runTelemetryCSVMenu converts a saved report, or every report in a
+// directory (public/reports_local by default), to CSV.
+func runTelemetryCSVMenu() {
+	reader := bufio.NewReader(os.Stdin)
+
+	def := MustPublicPath("reports_local")
+	fmt.Printf("Report file or directory [default %s]: ", def)
+	raw, _ := reader.ReadString('\n')
+	target := strings.TrimSpace(raw)
+	if target == "" {
+		target = def
+	}
+
+	fi, err := os.Stat(target)
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	files := []string{target}
+	if fi.IsDir() {
+		files = nil
+		entries, err := os.ReadDir(target)
+		if err != nil {
+			fmt.Println("❌", err)
+			return
+		}
+		for _, e := range entries {
+			if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") && !isRunContextFile(e.Name()) {
+				files = append(files, filepath.Join(target, e.Name()))
+			}
+		}
+	}
+	if len(files) == 0 {
+		fmt.Println("❌ No reports found in", target)
+		return
+	}
+
+	var done int
+	for _, f := range files {
+		out, err := convertReportToCSV(f)
+		if err != nil {
+			fmt.Printf("⚠️  %s: %v\n", filepath.Base(f), err)
+			continue
+		}
+		fmt.Printf("💾 Written → %s\n", out)
+		done++
+	}
+	fmt.Printf("✅ Converted %d/%d report(s)\n", done, len(files))
+}
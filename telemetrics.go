@@ -1,22 +1,37 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
+	"math"
+	"math/rand"
 	"mime/multipart"
 	"net/http"
+	"net/textproto"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/openfluke/paragon/v3"
 )
 
+// mnistLoadTimeout bounds the MNIST load in the telemetry pipeline, so a
+// stalled network mount fails the run instead of hanging it.
+const mnistLoadTimeout = 2 * time.Minute
+
 type TelemetrySource string
 
 const (
@@ -26,29 +41,41 @@ const (
 )
 
 type TelemetryReport struct {
-	Version    string          `json:"version"` // schema version
-	Source     TelemetrySource `json:"source"`  // native | wasm-bun | wasm-ionic
-	MachineID  string          `json:"machine_id"`
-	System     SystemInfo      `json:"system_info"`
-	FromHost   string          `json:"from_host"` // http://ip:port of the model host
-	ModelsUsed []string        `json:"models_used"`
-	Samples    []int           `json:"samples"` // digits 0..9 used (first index per digit)
-	StartedAt  time.Time       `json:"started_at"`
-	EndedAt    time.Time       `json:"ended_at"`
-	Notes      string          `json:"notes,omitempty"`
-	PerModel   []ModelRun      `json:"per_model"`
+	Version      string          `json:"version"` // schema version
+	Build        BuildInfo       `json:"build"`   // client build that produced this report
+	Source       TelemetrySource `json:"source"`  // native | wasm-bun | wasm-ionic
+	MachineID    string          `json:"machine_id"`
+	System       SystemInfo      `json:"system_info"`
+	FromHost     string          `json:"from_host"` // http://ip:port of the model host
+	ModelsUsed   []string        `json:"models_used"`
+	Samples      []int           `json:"samples"`               // digits 0..9 used
+	SampleMode   string          `json:"sample_mode,omitempty"` // "first" (default) | "nth:N" | "idx:a,b,…" | "balanced"
+	SampleSeed   int64           `json:"sample_seed,omitempty"` // seed used when sample_mode is "balanced"
+	StartedAt    time.Time       `json:"started_at"`
+	EndedAt      time.Time       `json:"ended_at"`
+	Notes        string          `json:"notes,omitempty"`
+	GPUFallback  bool            `json:"gpu_fallback"`            // any model's GPU path fell back to CPU
+	CPUOnly      bool            `json:"cpu_only,omitempty"`      // deliberate CPU-only baseline: "gpu" numbers are CPU by design
+	ThermalStart *ThermalSample  `json:"thermal_start,omitempty"` // --thermal: temperatures before the first model
+	ThermalEnd   *ThermalSample  `json:"thermal_end,omitempty"`   // --thermal: temperatures after the last model
+	PerModel     []ModelRun      `json:"per_model"`
 }
 
 type ModelRun struct {
 	ModelFile        string            `json:"model_file"`
 	WebGPUInitOK     bool              `json:"webgpu_init_ok"`
+	GPUFallback      bool              `json:"gpu_fallback"`       // true → the "gpu" timings/outputs are CPU fallback numbers
+	CPUOnly          bool              `json:"cpu_only,omitempty"` // WebGPU was skipped on purpose; "gpu" is a second CPU run
 	WebGPUInitTimeMS float64           `json:"webgpu_init_time_ms"`
-	CPU              []SampleTiming    `json:"cpu"` // per digit
-	GPU              []SampleTiming    `json:"gpu"` // per digit (may be CPU fallback if GPU init failed)
+	GPUWarmReused    bool              `json:"gpu_warm_reused,omitempty"`  // --gpu-warm reused a resident context: nothing was initialized, WebGPUInitTimeMS is 0
+	GPUWarmSyncMS    float64           `json:"gpu_warm_sync_ms,omitempty"` // weight copy + sync into the reused context
+	CPU              []SampleTiming    `json:"cpu"`                        // per digit
+	GPU              []SampleTiming    `json:"gpu"`                        // per digit (may be CPU fallback if GPU init failed)
 	Drift            []DriftMetrics    `json:"drift"`
-	ADHD10           ADHDScore         `json:"adhd10"`            // buckets + per-sample labels + summary across the 10 fixed samples
-	Summary          map[string]any    `json:"summary,omitempty"` // extra roll-ups if you want later
-	Meta             map[string]string `json:"meta,omitempty"`    // extra tags
+	ADHD10           ADHDScore         `json:"adhd10"`                      // buckets + per-sample labels + summary across the 10 fixed samples
+	Summary          map[string]any    `json:"summary,omitempty"`           // extra roll-ups if you want later
+	Meta             map[string]string `json:"meta,omitempty"`              // extra tags
+	NonFiniteValues  int               `json:"non_finite_values,omitempty"` // NaN/Inf replaced by 0 before writing (diverged run)
 }
 
 type SampleTiming struct {
@@ -72,7 +99,9 @@ type DriftMetrics struct {
 type ADHDScore struct {
 	Top1AccuracyCPU    float64 `json:"top1_accuracy_cpu"`
 	Top1AccuracyGPU    float64 `json:"top1_accuracy_gpu"`
-	CPUvsGPUAgreeCount int     `json:"cpu_vs_gpu_agree_count"`
+	CPUvsGPUAgreeCount int     `json:"cpu_vs_gpu_agree_count"`          // strict: identical argmax
+	TolerantAgreeCount int     `json:"cpu_vs_gpu_agree_tolerant_count"` // strict agreements + near-tie flips (see AgreeTolerance)
+	AgreeTolerance     float64 `json:"agree_tolerance"`                 // --agree-eps used for the tolerant count
 	AvgDriftMAE        float64 `json:"avg_drift_mae"`
 	MaxDriftMaxAbs     float64 `json:"max_drift_max_abs"`
 
@@ -101,14 +130,16 @@ type ADHDSample struct {
 	Idx       int    `json:"idx"`
 	CPUPred   int    `json:"cpu_pred"`
 	GPUPred   int    `json:"gpu_pred"`
-	CPUBucket string `json:"cpu_bucket"` // "correct" | "off_by_1" | "wrong"
-	GPUBucket string `json:"gpu_bucket"` // "
-	Agreement string `json:"agreement"`  // "agree" | "disagree"
+	CPUBucket string `json:"cpu_bucket"`         // "correct" | "off_by_1" | "wrong"
+	GPUBucket string `json:"gpu_bucket"`         // "
+	Agreement string `json:"agreement"`          // "agree" | "disagree"
+	TieFlip   bool   `json:"tie_flip,omitempty"` // disagreement where both picks were within --agree-eps on both devices
 }
 
 type modelManifest struct {
 	ID       string `json:"id"`
 	Filename string `json:"filename"`
+	Bytes    int64  `json:"bytes"`
 }
 
 // --- MNIST ensure/download helpers ---
@@ -121,19 +152,12 @@ var mnistFiles = []string{
 }
 
 func ensureLocalMNIST(hostBase string) error {
-	localDir := filepath.Join("public", "mnist")
+	localDir := MustPublicPath("mnist")
 	if err := os.MkdirAll(localDir, 0755); err != nil {
 		return err
 	}
 	// If all files already exist, we're done.
-	allPresent := true
-	for _, fn := range mnistFiles {
-		if _, err := os.Stat(filepath.Join(localDir, fn)); err != nil {
-			allPresent = false
-			break
-		}
-	}
-	if allPresent {
+	if len(missingMNISTFiles(localDir)) == 0 {
 		return nil
 	}
 	// Pull each missing file from host /mnist/<name>
@@ -148,13 +172,36 @@ func ensureLocalMNIST(hostBase string) error {
 			return fmt.Errorf("mnist download failed: %s -> %s: %w", src, dst, err)
 		}
 	}
+	// Fresh files on disk; make sure nobody keeps serving a stale cache.
+	invalidateMNIST()
 	return nil
 }
 
+// TelemetryOptions tweak how RunTelemetryPipeline picks its fixed samples.
+// The zero value keeps the original protocol (first index per digit).
+type TelemetryOptions struct {
+	Balanced   bool  // draw one sample per digit with balancedSample
+	SampleSeed int64 // seed for Balanced; recorded in the report for reproducibility
+	Cleanup    bool  // delete the downloaded models from models_remote after a successful upload
+	CSV        bool  // also write the per-sample rows as CSV next to the local report
+	Prometheus bool  // also write Prometheus metrics (.prom) next to the local report
+	CPUOnly    bool  // skip WebGPU: both the "cpu" and "gpu" paths run on CPU (fleet baseline)
+}
+
 // ---- public API ----
 
 // Pull models from host, run telemetry, save local JSON, and push back.
-func RunTelemetryPipeline(hostBase string, source TelemetrySource) (string, error) {
+func RunTelemetryPipeline(hostBase string, source TelemetrySource, opts TelemetryOptions) (string, error) {
+	hostBase, err := normalizeHostBase(hostBase)
+	if err != nil {
+		return "", err
+	}
+
+	// 0) warn if the host has a newer build than this client
+	checkHostVersion(hostBase)
+	runCtx := captureRunContext()
+	runCtx.Operation = "telemetry"
+
 	// 1) fetch manifest and download models
 	modelDirLocal := MustPublicPath("models_remote")
 	fmt.Printf("📂 Remote models directory: %s\n", modelDirLocal)
@@ -168,29 +215,37 @@ func RunTelemetryPipeline(hostBase string, source TelemetrySource) (string, erro
 		return "", fmt.Errorf("fetch manifest: %w", err)
 	}
 	if len(manifest) == 0 {
-		return "", fmt.Errorf("manifest empty at %s", hostBase)
+		return "", fmt.Errorf("%w at %s", ErrManifestEmpty, hostBase)
 	}
 
 	fmt.Printf("📥 Downloading %d models from %s\n", len(manifest), hostBase)
 
-	var modelFiles []string
+	var modelFiles, modelNames []string
 	for _, m := range manifest {
 		if m.Filename == "" {
 			continue
 		}
-		url := strings.TrimRight(hostBase, "/") + "/models/" + m.Filename
-		dst := filepath.Join(modelDirLocal, m.Filename)
+		// Filenames may carry subpaths ("exp1/mnist_S1.json"); keep them, but
+		// never let a manifest write outside models_remote.
+		if !safeModelRelPath(m.Filename) {
+			fmt.Printf("⚠️  Skipping unsafe manifest path %q\n", m.Filename)
+			continue
+		}
+		url := strings.TrimRight(hostBase, "/") + "/models/" + strings.TrimLeft(filepath.ToSlash(m.Filename), "/")
+		dst := filepath.Join(modelDirLocal, filepath.FromSlash(m.Filename))
 
 		fmt.Printf("   Downloading %s...\n", m.Filename)
 		if err := httpDownload(url, dst); err != nil {
 			return "", fmt.Errorf("download %s: %w", m.Filename, err)
 		}
 		modelFiles = append(modelFiles, dst)
+		modelNames = append(modelNames, filepath.ToSlash(m.Filename))
 	}
 	fmt.Printf("✅ Downloaded %d model files\n", len(modelFiles))
+	runCtx.Models = checksumDir(modelDirLocal, func(name string) bool { return strings.HasSuffix(name, ".json") })
 
 	// 2) collect system info & machine id
-	sys := Collect()
+	sys := withWebGPUProbe(Collect())
 	machineID := hashSystemInfo(sys)
 	fmt.Printf("🖥️  Machine ID: %s\n", machineID)
 
@@ -203,54 +258,96 @@ func RunTelemetryPipeline(hostBase string, source TelemetrySource) (string, erro
 	}
 	fmt.Printf("✅ MNIST data ready\n")
 
-	// 3) prepare samples: first index per digit (0..9)
+	// 3) prepare samples: one index per digit (0..9), first occurrence or balanced draw
 	fmt.Printf("📊 Loading MNIST dataset...\n")
-	images, labels, err := loadMNISTData(mnistDir)
+	loadCtx, cancelLoad := context.WithTimeout(context.Background(), mnistLoadTimeout)
+	images, labels, err := getMNISTCtx(loadCtx)
+	cancelLoad()
 	if err != nil {
 		return "", fmt.Errorf("load mnist: %w", err)
 	}
 	fmt.Printf("   Loaded %d samples\n", len(images))
 
-	firstIdx := firstIndexPerDigit(labels)
+	sampleMode := sampleSelectionLabel()
+	firstIdx, err := sampleIndexPerDigit(labels)
+	if err != nil {
+		return "", fmt.Errorf("select samples: %w", err)
+	}
+	if opts.Balanced {
+		sampleMode = "balanced"
+		firstIdx = make(map[int]int)
+		runCtx.Seeds["sample"] = opts.SampleSeed
+		for _, idx := range balancedSample(labels, 1, opts.SampleSeed) {
+			firstIdx[argmax64(labels[idx][0])] = idx
+		}
+		fmt.Printf("   Balanced sample (seed %d): %v\n", opts.SampleSeed, firstIdx)
+	}
 	var digits []int
 	for d := 0; d <= 9; d++ {
 		digits = append(digits, d)
 	}
 
 	// 4) run for each model
+	thermalStart := sampleThermal()
 	start := time.Now()
 	fmt.Printf("🧪 Running telemetry on %d models...\n", len(modelFiles))
 
 	var per []ModelRun
 	for i, mf := range modelFiles {
-		fmt.Printf("\n[%d/%d] Processing %s\n", i+1, len(modelFiles), filepath.Base(mf))
+		fmt.Printf("\n[%d/%d] Processing %s\n", i+1, len(modelFiles), modelNames[i])
 
-		mr, err := runModelTelemetry(mf, images, firstIdx)
+		mr, err := runModelTelemetry(mf, images, firstIdx, opts.CPUOnly)
 		if err != nil {
-			fmt.Printf("⚠️  model %s: %v\n", filepath.Base(mf), err)
+			fmt.Printf("⚠️  model %s: %v\n", modelNames[i], err)
 			continue
 		}
+		mr.ModelFile = modelNames[i]
 		// ADHD-style: buckets + per-sample labels + summary across the 10 fixed samples
 		mr.ADHD10 = computeADHD10(mr)
 		per = append(per, mr)
+		emitEvent("telemetry_model", mr)
 
 		fmt.Printf("   CPU Accuracy: %.2f%% | GPU Accuracy: %.2f%%\n",
 			mr.ADHD10.Top1AccuracyCPU, mr.ADHD10.Top1AccuracyGPU)
 	}
 	end := time.Now()
 	fmt.Printf("\n✅ Telemetry complete in %v\n", end.Sub(start))
+	thermalEnd := sampleThermal()
+	if thermalStart != nil && thermalEnd != nil {
+		fmt.Printf("🌡️  Hottest sensor: %.1f°C → %.1f°C\n", thermalStart.maxCelsius(), thermalEnd.maxCelsius())
+	} else if thermalEnabled() {
+		fmt.Println("ℹ️  --thermal: no temperature sensors readable on this machine")
+	}
 
 	report := TelemetryReport{
 		Version:    "1.2.0",
+		Build:      currentBuild(),
 		Source:     source,
 		MachineID:  machineID,
 		System:     sys,
 		FromHost:   hostBase,
-		ModelsUsed: baseNames(modelFiles),
+		ModelsUsed: modelNames,
 		Samples:    digits,
+		SampleMode: sampleMode,
+		SampleSeed: ternary(opts.Balanced, opts.SampleSeed, 0),
 		StartedAt:  start.UTC(),
 		EndedAt:    end.UTC(),
 		PerModel:   per,
+		CPUOnly:    opts.CPUOnly,
+
+		ThermalStart: thermalStart,
+		ThermalEnd:   thermalEnd,
+	}
+	if opts.CPUOnly {
+		report.Notes = "CPU-only baseline: WebGPU skipped on purpose; the gpu timings/outputs are a second CPU run"
+	}
+	for _, mr := range per {
+		if mr.GPUFallback {
+			report.GPUFallback = true
+			report.Notes = "GPU fallback: one or more models ran their GPU side on CPU (see per_model[].gpu_fallback)"
+			fmt.Println("⚠️  Report flagged gpu_fallback: some GPU numbers are really CPU numbers.")
+			break
+		}
 	}
 
 	// 5) save locally
@@ -265,70 +362,145 @@ func RunTelemetryPipeline(hostBase string, source TelemetrySource) (string, erro
 	localPath := filepath.Join(outDir, fn)
 	fmt.Printf("💾 Saving report to: %s\n", localPath)
 
+	if n := sanitizeReport(&report); n > 0 {
+		fmt.Printf("⚠️  Replaced %d NaN/Inf value(s) with 0 — see non_finite_values per model; outputs likely diverged\n", n)
+	}
 	if err := writeJSON(localPath, report); err != nil {
 		return "", fmt.Errorf("failed to write report: %w", err)
 	}
+	if err := validateReportFile(localPath); err != nil {
+		return "", fmt.Errorf("report %s failed validation, not uploading: %w", localPath, err)
+	}
 	fmt.Printf("✅ Report saved locally\n")
+	if err := writeRunContext(localPath, runCtx); err != nil {
+		fmt.Printf("⚠️  Could not write run context: %v\n", err)
+	}
+	if opts.CSV {
+		if csvPath, err := writeTelemetryCSV(localPath, report); err != nil {
+			fmt.Printf("⚠️  Could not write CSV: %v\n", err)
+		} else {
+			fmt.Printf("💾 CSV → %s\n", csvPath)
+		}
+	}
+	if opts.Prometheus {
+		if promPath, err := writeTelemetryProm(localPath, report); err != nil {
+			fmt.Printf("⚠️  Could not write Prometheus metrics: %v\n", err)
+		} else {
+			fmt.Printf("💾 Prometheus → %s\n", promPath)
+		}
+	}
 
 	// 6) push back to host (multipart POST /upload)
 	fmt.Printf("📤 Uploading report to %s...\n", hostBase)
-	if err := uploadFile(hostBase, localPath, fn); err != nil {
-		return "", fmt.Errorf("push report: %w", err)
+	if err := uploadWithRetry(hostBase, localPath, fn); err != nil {
+		// the report is safe on disk; hand its path back with the error
+		return localPath, fmt.Errorf("push report: %w", err)
+	}
+	if err := markUploaded(localPath, hostBase); err != nil {
+		fmt.Printf("⚠️  Could not mark report as uploaded: %v\n", err)
 	}
 	fmt.Printf("✅ Report uploaded successfully\n")
 
+	// 7) optional housekeeping: the models are re-downloaded next run anyway
+	if opts.Cleanup {
+		removed := 0
+		for _, mf := range modelFiles {
+			if err := os.Remove(mf); err != nil {
+				fmt.Printf("⚠️  cleanup %s: %v\n", mf, err)
+				continue
+			}
+			removed++
+		}
+		fmt.Printf("🧹 Removed %d downloaded model(s) from %s\n", removed, modelDirLocal)
+	}
+
 	return localPath, nil
 }
 
+// normalizeHostBase turns what a user typed as the telemetry target into
+// "scheme://host[:port]": the scheme defaults to http, any path, query or
+// fragment is dropped, and the host and port are checked so typos fail here
+// with ErrInvalidHost instead of as a cryptic download error later.
+func normalizeHostBase(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", fmt.Errorf("%w: empty", ErrInvalidHost)
+	}
+	raw := s
+	if !strings.Contains(s, "://") {
+		s = "http://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("%w %q", ErrInvalidHost, raw)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("%w %q: scheme must be http or https", ErrInvalidHost, raw)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("%w %q: missing host", ErrInvalidHost, raw)
+	}
+	if p := u.Port(); p != "" {
+		if n, err := strconv.Atoi(p); err != nil || n < 1 || n > 65535 {
+			return "", fmt.Errorf("%w %q: bad port %q", ErrInvalidHost, raw, p)
+		}
+	} else if strings.HasSuffix(u.Host, ":") {
+		return "", fmt.Errorf("%w %q: empty port", ErrInvalidHost, raw)
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
+
 // ---- internals ----
 
-func runModelTelemetry(modelPath string, images [][][]float64, firstIdx map[int]int) (ModelRun, error) {
+// runModelTelemetry times one model's CPU and GPU forwards on the fixed digit
+// samples. With cpuOnly the "gpu" side is a second CPU network and the run is
+// flagged cpu_only rather than gpu_fallback.
+func runModelTelemetry(modelPath string, images [][][]float64, firstIdx map[int]int, cpuOnly bool) (ModelRun, error) {
 	// Load saved network (float32)
 	loaded, err := paragon.LoadNamedNetworkFromJSONFile(modelPath)
 	if err != nil {
 		return ModelRun{}, fmt.Errorf("load: %w", err)
 	}
-	tmp, ok := loaded.(*paragon.Network[float32])
-	if !ok {
-		return ModelRun{}, fmt.Errorf("not float32: %T", loaded)
-	}
 
 	// Rebuild fresh network to ensure GPU-safe buffers
-	shapes := make([]struct{ Width, Height int }, len(tmp.Layers))
-	acts := make([]string, len(tmp.Layers))
-	trains := make([]bool, len(tmp.Layers))
-	for i, L := range tmp.Layers {
-		shapes[i] = struct{ Width, Height int }{L.Width, L.Height}
-		a := "linear"
-		if L.Height > 0 && L.Width > 0 && L.Neurons[0][0] != nil {
-			a = L.Neurons[0][0].Activation
-		}
-		acts[i], trains[i] = a, true
+	nnCPU, err := rebuildFloat32(loaded)
+	if err != nil {
+		return ModelRun{}, err
 	}
-	nnCPU, _ := paragon.NewNetwork[float32](shapes, acts, trains)
-	state, _ := tmp.MarshalJSONModel()
-	_ = nnCPU.UnmarshalJSONModel(state)
 
 	// Clone for GPU
-	nnGPU, _ := paragon.NewNetwork[float32](shapes, acts, trains)
-	_ = nnGPU.UnmarshalJSONModel(state)
+	nnGPU, err := rebuildFloat32(loaded)
+	if err != nil {
+		return ModelRun{}, err
+	}
 	nnGPU.WebGPUNative = true
 
-	var gpuInitOK bool
+	var gpuInitOK, warmReused bool
 	startInit := time.Now()
-	if err := nnGPU.InitializeOptimizedGPU(); err != nil {
+	if cpuOnly {
+		nnGPU.WebGPUNative = false
+	} else if gnn, release, err := initGPUNet(nnGPU); err != nil {
 		gpuInitOK = false
 		nnGPU.WebGPUNative = false
+		warnGPUFallback("telemetry "+filepath.Base(modelPath), err)
 	} else {
+		warmReused = gnn != nnGPU
+		nnGPU = gnn
+		defer release()
 		gpuInitOK = true
 		// warmup cost once (pick any sample)
 		if idx, ok := firstIdx[0]; ok {
 			nnGPU.Forward(images[idx])
 			_ = nnGPU.ExtractOutput()
 		}
-		defer nnGPU.CleanupOptimizedGPU()
 	}
 	initMS := float64(time.Since(startInit).Microseconds()) / 1000.0
+	recordTiming("init", time.Since(startInit))
+	var warmSyncMS float64
+	if warmReused {
+		// a weight sync, not an init; keep it out of webgpu_init_time_ms
+		warmSyncMS, initMS = initMS, 0
+	}
 
 	// per-digit timings and drift
 	var cpuTimes []SampleTiming
@@ -367,12 +539,19 @@ func runModelTelemetry(modelPath string, images [][][]float64, firstIdx map[int]
 
 		mx, mae := driftMaxAndMAE(outCPU, outGPU)
 		drift = append(drift, DriftMetrics{Digit: d, Idx: idx, MaxAbs: mx, MAE: mae})
+		if mx > driftThreshold() {
+			printDriftBreakdown(d, outCPU, outGPU)
+		}
 	}
 
 	return ModelRun{
 		ModelFile:        filepath.Base(modelPath),
 		WebGPUInitOK:     gpuInitOK,
+		GPUFallback:      !gpuInitOK && !cpuOnly,
+		CPUOnly:          cpuOnly,
 		WebGPUInitTimeMS: initMS,
+		GPUWarmReused:    warmReused,
+		GPUWarmSyncMS:    warmSyncMS,
 		CPU:              cpuTimes,
 		GPU:              gpuTimes,
 		Drift:            drift,
@@ -380,22 +559,207 @@ func runModelTelemetry(modelPath string, images [][][]float64, firstIdx map[int]
 }
 
 func firstIndexPerDigit(labels [][][]float64) map[int]int {
-	firstIdx := make(map[int]int)
+	firstIdx, _ := selectIndexPerDigit(labels, 1, nil)
+	return firstIdx
+}
+
+// Which sample per digit the fixed 0..9 protocol uses (compare, digit
+// benchmark, telemetry). Default is the first occurrence; these let you probe
+// harder samples without touching code.
+var (
+	flagSampleNth = flag.Int("sample-nth", 1, "Use the Nth occurrence of each digit as its sample (1 = first)")
+	flagSampleIdx = flag.String("sample-idx", "", "Comma-separated dataset indices to use as the digit samples (overrides --sample-nth)")
+)
+
+// sampleIndexPerDigit is the flag-driven selector: --sample-idx if given,
+// otherwise the --sample-nth occurrence (first when neither flag is set).
+func sampleIndexPerDigit(labels [][][]float64) (map[int]int, error) {
+	nth := 1
+	var explicit []int
+	if flag.Parsed() {
+		nth = *flagSampleNth
+		if s := strings.TrimSpace(*flagSampleIdx); s != "" {
+			for _, part := range strings.Split(s, ",") {
+				v, err := strconv.Atoi(strings.TrimSpace(part))
+				if err != nil {
+					return nil, fmt.Errorf("--sample-idx: bad index %q", part)
+				}
+				explicit = append(explicit, v)
+			}
+		}
+	}
+	return selectIndexPerDigit(labels, nth, explicit)
+}
+
+// sampleSelectionLabel describes the active selection for reports/headers.
+func sampleSelectionLabel() string {
+	if !flag.Parsed() {
+		return "first"
+	}
+	if s := strings.TrimSpace(*flagSampleIdx); s != "" {
+		return "idx:" + s
+	}
+	if *flagSampleNth > 1 {
+		return fmt.Sprintf("nth:%d", *flagSampleNth)
+	}
+	return "first"
+}
+
+// parseDigits reads a digit subset such as "4,9", "3-5" or "0-2,7" (empty:
+// all of 0..9) and returns the digits in ascending order, deduplicated.
+func parseDigits(s string) ([]int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return allDigits(), nil
+	}
+	var seen [10]bool
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		lo, hi, isRange := strings.Cut(part, "-")
+		a, errA := strconv.Atoi(strings.TrimSpace(lo))
+		b, errB := a, error(nil)
+		if isRange {
+			b, errB = strconv.Atoi(strings.TrimSpace(hi))
+		}
+		if errA != nil || errB != nil || a < 0 || b > 9 || a > b {
+			return nil, fmt.Errorf("bad digit or range %q (want 0-9, e.g. 4,9 or 3-5)", part)
+		}
+		for d := a; d <= b; d++ {
+			seen[d] = true
+		}
+	}
+	var out []int
+	for d, ok := range seen {
+		if ok {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+// allDigits is the full 0..9 protocol.
+func allDigits() []int {
+	return []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+}
+
+// selectDigits keeps only the given digits of a digit → index selection;
+// nil digits keeps all of them.
+func selectDigits(sel map[int]int, digits []int) map[int]int {
+	if digits == nil {
+		return sel
+	}
+	out := make(map[int]int, len(digits))
+	for _, d := range digits {
+		if idx, ok := sel[d]; ok {
+			out[d] = idx
+		}
+	}
+	return out
+}
+
+// digitsLabel renders a subset for headers, "0-9" for the full set.
+func digitsLabel(digits []int) string {
+	if digits == nil || len(digits) == 10 {
+		return "0-9"
+	}
+	parts := make([]string, len(digits))
+	for i, d := range digits {
+		parts[i] = strconv.Itoa(d)
+	}
+	return strings.Join(parts, ",")
+}
+
+// promptDigits asks for a digit subset; ok is false (after printing why)
+// when the answer doesn't parse.
+func promptDigits(reader *bufio.Reader) (digits []int, ok bool) {
+	fmt.Print("Digits [default 0-9]: ")
+	raw, _ := reader.ReadString('\n')
+	digits, err := parseDigits(raw)
+	if err != nil {
+		fmt.Println("❌", err)
+		return nil, false
+	}
+	return digits, true
+}
+
+// selectIndexPerDigit maps digit → dataset index. With explicit indices each
+// one is assigned to its label's digit (two indices for one digit is an
+// error); otherwise the nth (1-based) occurrence of each digit is used.
+// Digits with fewer than nth occurrences are simply absent from the map.
+func selectIndexPerDigit(labels [][][]float64, nth int, explicit []int) (map[int]int, error) {
+	sel := make(map[int]int)
+	if len(explicit) > 0 {
+		for _, idx := range explicit {
+			if idx < 0 || idx >= len(labels) {
+				return nil, fmt.Errorf("sample index %d out of range [0,%d)", idx, len(labels))
+			}
+			d := argmax64(labels[idx][0])
+			if prev, dup := sel[d]; dup {
+				return nil, fmt.Errorf("indices %d and %d are both digit %d", prev, idx, d)
+			}
+			sel[d] = idx
+		}
+		return sel, nil
+	}
+
+	if nth < 1 {
+		return nil, fmt.Errorf("sample occurrence must be ≥ 1, got %d", nth)
+	}
+	seen := make(map[int]int)
 	for i, lbl := range labels {
 		for d, v := range lbl[0] {
 			if v == 1.0 {
-				if _, seen := firstIdx[d]; !seen {
-					firstIdx[d] = i
+				seen[d]++
+				if seen[d] == nth {
+					sel[d] = i
 				}
 				break
 			}
 		}
 	}
-	return firstIdx
+	return sel, nil
 }
 
+// balancedSample returns perClass dataset indices for every digit present in
+// labels, drawn uniformly within each class. The same labels+seed always give
+// the same indices, ordered by digit.
+func balancedSample(labels [][][]float64, perClass int, seed int64) []int {
+	byClass := make(map[int][]int)
+	for i, lbl := range labels {
+		if len(lbl) == 0 || len(lbl[0]) == 0 {
+			continue
+		}
+		d := argmax64(lbl[0])
+		byClass[d] = append(byClass[d], i)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	var out []int
+	for d := 0; d <= 9; d++ {
+		idxs := byClass[d]
+		if len(idxs) == 0 {
+			continue
+		}
+		n := perClass
+		if n > len(idxs) {
+			n = len(idxs)
+		}
+		// partial Fisher–Yates on a copy: only the first n slots matter
+		pool := append([]int(nil), idxs...)
+		for k := 0; k < n; k++ {
+			j := k + rng.Intn(len(pool)-k)
+			pool[k], pool[j] = pool[j], pool[k]
+		}
+		out = append(out, pool[:n]...)
+	}
+	return out
+}
+
+// top1 is the highest output value (the confidence of the prediction), or 0
+// with a warning for an empty output.
 func top1(out []float64) float64 {
 	if len(out) == 0 {
+		fmt.Println("⚠️  Empty model output; top-1 score set to 0")
 		return 0
 	}
 	best := out[0]
@@ -407,6 +771,29 @@ func top1(out []float64) float64 {
 	return best
 }
 
+var flagAgreeEps = flag.Float64("agree-eps", 1e-4, "Count a CPU/GPU argmax flip as a benign tie when both picks' outputs are within this of each other on both devices (0 = strict only)")
+
+func agreeEps() float64 {
+	if !flag.Parsed() || *flagAgreeEps < 0 {
+		return 0
+	}
+	return *flagAgreeEps
+}
+
+// isTieFlip reports whether a CPU/GPU prediction flip is a numerical near-tie
+// rather than a real disagreement: on both devices, the outputs at the two
+// predicted classes are within eps of each other.
+func isTieFlip(c, g SampleTiming, eps float64) bool {
+	if eps <= 0 || c.Pred == g.Pred || c.Pred < 0 || g.Pred < 0 {
+		return false
+	}
+	if c.Pred >= len(c.Output) || g.Pred >= len(c.Output) || c.Pred >= len(g.Output) || g.Pred >= len(g.Output) {
+		return false
+	}
+	return math.Abs(c.Output[c.Pred]-c.Output[g.Pred]) <= eps &&
+		math.Abs(g.Output[g.Pred]-g.Output[c.Pred]) <= eps
+}
+
 // ADHD-style buckets + per-sample labels over the 10 fixed samples
 func computeADHD10(m ModelRun) ADHDScore {
 	if len(m.CPU) == 0 || len(m.GPU) == 0 || len(m.Drift) == 0 {
@@ -414,8 +801,9 @@ func computeADHD10(m ModelRun) ADHDScore {
 	}
 
 	var accCPU, accGPU float64
-	var agreeCount int
+	var agreeCount, tolerantCount int
 	var sumMAE, maxMaxAbs float64
+	eps := agreeEps()
 	n := 0
 
 	var buckets ADHDBuckets
@@ -443,20 +831,24 @@ func computeADHD10(m ModelRun) ADHDScore {
 		}
 
 		// nuance: off-by-1
-		if absInt(c.Pred-c.Digit) == 1 {
+		if c.Pred >= 0 && absInt(c.Pred-c.Digit) == 1 {
 			buckets.CPUOffBy1++
 		}
-		if absInt(g.Pred-g.Digit) == 1 {
+		if g.Pred >= 0 && absInt(g.Pred-g.Digit) == 1 {
 			buckets.GPUOffBy1++
 		}
 
 		// agreement between CPU/GPU predictions
 		agree := (c.Pred == g.Pred)
+		tie := isTieFlip(c, g, eps)
 		if agree {
 			agreeCount++
 		} else {
 			buckets.Disagree++
 		}
+		if agree || tie {
+			tolerantCount++
+		}
 		buckets.Agree = agreeCount // keep in sync
 
 		// drift rollups
@@ -474,6 +866,7 @@ func computeADHD10(m ModelRun) ADHDScore {
 			CPUBucket: labelBucket(c.Pred, c.Digit),
 			GPUBucket: labelBucket(g.Pred, g.Digit),
 			Agreement: ternary(agree, "agree", "disagree"),
+			TieFlip:   tie,
 		})
 
 		n++
@@ -483,6 +876,8 @@ func computeADHD10(m ModelRun) ADHDScore {
 		Top1AccuracyCPU:    safeDiv(accCPU, float64(n)),
 		Top1AccuracyGPU:    safeDiv(accGPU, float64(n)),
 		CPUvsGPUAgreeCount: agreeCount,
+		TolerantAgreeCount: tolerantCount,
+		AgreeTolerance:     eps,
 		AvgDriftMAE:        safeDiv(sumMAE, float64(n)),
 		MaxDriftMaxAbs:     maxMaxAbs,
 		Buckets:            buckets,
@@ -498,6 +893,9 @@ func absInt(x int) int {
 }
 
 func labelBucket(pred, label int) string {
+	if pred < 0 {
+		return "no_output"
+	}
 	if pred == label {
 		return "correct"
 	}
@@ -517,13 +915,96 @@ func ternary[T any](cond bool, a, b T) T {
 // stable machine ID from normalized SystemInfo
 func hashSystemInfo(si SystemInfo) string {
 	clone := si
+	clone.Build = BuildInfo{}                              // same machine, new binary → same ID
+	clone.GPUDriverVersion = ""                            // a driver update shouldn't turn it into a new machine
+	clone.WebGPUUsable, clone.WebGPUProbeError = false, "" // nor a driver that breaks/fixes WebGPU
+	// VRAM comes from whichever tool answered (nvidia-smi, or an lspci BAR
+	// size), so it can differ between runs on the same machine; 0 is unknown.
+	clone.GPUMemoryBytes = 0
+	// Core counts postdate the ID, and the runtime.NumCPU fallback follows
+	// CPU affinity (taskset, cgroups); keep them out so IDs stay stable.
+	clone.PhysicalCores, clone.LogicalCores = 0, 0
+	// A container or VM is a different machine from its host; bare metal
+	// hashes as before the field existed.
+	if clone.Virtualization == "none" {
+		clone.Virtualization = ""
+	}
+	if si.GPUs != nil {
+		clone.GPUs = make([]map[string]string, len(si.GPUs))
+		for i, g := range si.GPUs {
+			m := make(map[string]string, len(g))
+			for k, v := range g {
+				if k != "vram_bytes" {
+					m[k] = v
+				}
+			}
+			clone.GPUs[i] = m
+		}
+	}
 	clone.GPUModel = strings.ToLower(clone.GPUModel)
 	clone.CPUModel = strings.ToLower(clone.CPUModel)
-	b, _ := json.Marshal(clone)
+	// Build is a struct, so omitempty can't drop it; shadow it with a nil
+	// pointer to keep the "build" key out of the hash.
+	b, _ := json.Marshal(struct {
+		SystemInfo
+		Build *BuildInfo `json:"build,omitempty"`
+	}{SystemInfo: clone})
 	sum := md5.Sum(b)
 	return hex.EncodeToString(sum[:])
 }
 
+// sanitizeReport replaces NaN/±Inf (which json.Marshal rejects) with 0 in
+// every float of the per-model runs, counting them per model. Returns the
+// total number of values replaced.
+func sanitizeReport(r *TelemetryReport) int {
+	total := 0
+	for i := range r.PerModel {
+		mr := &r.PerModel[i]
+		n := 0
+		fix := func(v *float64) {
+			if math.IsNaN(*v) || math.IsInf(*v, 0) {
+				*v = 0
+				n++
+			}
+		}
+		for _, ts := range [][]SampleTiming{mr.CPU, mr.GPU} {
+			for j := range ts {
+				fix(&ts[j].ElapsedMS)
+				fix(&ts[j].Top1Score)
+				for k := range ts[j].Output {
+					fix(&ts[j].Output[k])
+				}
+			}
+		}
+		for j := range mr.Drift {
+			fix(&mr.Drift[j].MaxAbs)
+			fix(&mr.Drift[j].MAE)
+		}
+		fix(&mr.WebGPUInitTimeMS)
+		fix(&mr.GPUWarmSyncMS)
+		fix(&mr.ADHD10.Top1AccuracyCPU)
+		fix(&mr.ADHD10.Top1AccuracyGPU)
+		fix(&mr.ADHD10.AvgDriftMAE)
+		fix(&mr.ADHD10.MaxDriftMaxAbs)
+		mr.NonFiniteValues += n
+		total += n
+	}
+	return total
+}
+
+// validateReportFile re-reads a written report and checks it parses back.
+func validateReportFile(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var back TelemetryReport
+	if err := json.Unmarshal(b, &back); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return nil
+}
+
 // ---- math/util helpers ----
 
 func safeDiv(a, b float64) float64 {
@@ -560,9 +1041,66 @@ func pow10(n int) float64 {
 
 // ---- HTTP helpers ----
 
+var (
+	telemetryHeadersOnce sync.Once
+	telemetryHeadersVal  http.Header
+)
+
+// telemetryHeaders parses TELEMETRY_HEADERS (a JSON object of header name →
+// value, e.g. {"X-Api-Key":"…"}) once. A malformed value is reported and
+// ignored rather than failing every request.
+func telemetryHeaders() http.Header {
+	telemetryHeadersOnce.Do(func() {
+		raw := strings.TrimSpace(os.Getenv("TELEMETRY_HEADERS"))
+		if raw == "" {
+			return
+		}
+		var m map[string]string
+		if err := json.Unmarshal([]byte(raw), &m); err != nil {
+			fmt.Printf("⚠️  Ignoring TELEMETRY_HEADERS (want a JSON object of strings): %v\n", err)
+			return
+		}
+		telemetryHeadersVal = make(http.Header, len(m))
+		for k, v := range m {
+			telemetryHeadersVal.Set(k, v)
+		}
+	})
+	return telemetryHeadersVal
+}
+
+// newTelemetryRequest builds a request to a model host with the
+// TELEMETRY_HEADERS applied. Every telemetry call (manifest, model and MNIST
+// downloads, upload, version, link probe) goes through it, so a gateway that
+// wants e.g. an API key sees it on all of them.
+func newTelemetryRequest(method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range telemetryHeaders() {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	return req, nil
+}
+
+// telemetryGet is GET url via client (http.DefaultClient when nil) with the
+// TELEMETRY_HEADERS applied.
+func telemetryGet(client *http.Client, url string) (*http.Response, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := newTelemetryRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
+
 func fetchManifest(hostBase string) ([]modelManifest, error) {
 	u := strings.TrimRight(hostBase, "/") + "/models/manifest.json"
-	resp, err := http.Get(u)
+	resp, err := telemetryGet(nil, u)
 	if err != nil {
 		return nil, err
 	}
@@ -578,7 +1116,7 @@ func fetchManifest(hostBase string) ([]modelManifest, error) {
 }
 
 func httpDownload(url, dst string) error {
-	resp, err := http.Get(url)
+	resp, err := telemetryGet(nil, url)
 	if err != nil {
 		return err
 	}
@@ -598,12 +1136,71 @@ func httpDownload(url, dst string) error {
 	return err
 }
 
+// uploadStatusError is a non-200 answer from /upload.
+type uploadStatusError struct {
+	Code   int
+	Status string
+	Body   string
+}
+
+func (e *uploadStatusError) Error() string {
+	return fmt.Sprintf("upload failed: %s — %s", e.Status, e.Body)
+}
+
+// Upload retry budget, separate from downloads: a briefly overloaded host
+// shouldn't cost a run its results, but a host that is really down shouldn't
+// stall the client for minutes either.
+const (
+	uploadAttempts   = 5
+	uploadBackoff    = time.Second
+	uploadBackoffMax = 15 * time.Second
+)
+
+// uploadRetryable reports whether a failed upload is worth another try:
+// network errors, 408, 429 and 5xx are; other 4xx (a rejected report) are not.
+func uploadRetryable(err error) bool {
+	var se *uploadStatusError
+	if !errors.As(err, &se) {
+		return true
+	}
+	return se.Code == http.StatusRequestTimeout || se.Code == http.StatusTooManyRequests || se.Code >= 500
+}
+
+// uploadWithRetry is uploadFile with exponential backoff. Once the budget is
+// spent (or the host rejects the report outright) it gives up and returns an
+// ErrUploadFailed-wrapped error; the caller keeps the local copy.
+func uploadWithRetry(hostBase, path, name string) error {
+	wait := uploadBackoff
+	var err error
+	for attempt := 1; attempt <= uploadAttempts; attempt++ {
+		if err = uploadFile(hostBase, path, name); err == nil {
+			return nil
+		}
+		if !uploadRetryable(err) || attempt == uploadAttempts {
+			break
+		}
+		fmt.Printf("⚠️  Upload attempt %d/%d failed (%v); retrying in %v\n", attempt, uploadAttempts, err, wait)
+		time.Sleep(wait)
+		wait = min(wait*2, uploadBackoffMax)
+	}
+	return fmt.Errorf("%w: %w", ErrUploadFailed, err)
+}
+
+// uploadFile POSTs path to hostBase/upload as multipart field "file",
+// gzip-compressed. The part is named "<name>.gz" and carries
+// Content-Encoding: gzip; hosts that know about it store it decompressed as
+// name, older hosts simply keep the .gz file.
 func uploadFile(hostBase, path, name string) error {
 	u := strings.TrimRight(hostBase, "/") + "/upload"
+	gzName := name + ".gz"
 
 	var buf bytes.Buffer
 	w := multipart.NewWriter(&buf)
-	fw, err := w.CreateFormFile("file", filepath.Base(name))
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, filepath.Base(gzName)))
+	h.Set("Content-Type", "application/gzip")
+	h.Set("Content-Encoding", "gzip")
+	fw, err := w.CreatePart(h)
 	if err != nil {
 		return err
 	}
@@ -612,13 +1209,20 @@ func uploadFile(hostBase, path, name string) error {
 		return err
 	}
 	defer f.Close()
-	if _, err := io.Copy(fw, f); err != nil {
+	before := buf.Len()
+	gz := gzip.NewWriter(fw)
+	raw, err := io.Copy(gz, f)
+	if err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
 		return err
 	}
-	_ = w.WriteField("name", name)
+	fmt.Printf("   gzip: %d → %d bytes\n", raw, buf.Len()-before)
+	_ = w.WriteField("name", gzName)
 	_ = w.Close()
 
-	req, err := http.NewRequest(http.MethodPost, u, &buf)
+	req, err := newTelemetryRequest(http.MethodPost, u, &buf)
 	if err != nil {
 		return err
 	}
@@ -631,15 +1235,14 @@ func uploadFile(hostBase, path, name string) error {
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("upload failed: %s — %s", resp.Status, strings.TrimSpace(string(body)))
+		return &uploadStatusError{Code: resp.StatusCode, Status: resp.Status, Body: strings.TrimSpace(string(body))}
 	}
-	return nil
-}
-
-func baseNames(paths []string) []string {
-	out := make([]string, 0, len(paths))
-	for _, p := range paths {
-		out = append(out, filepath.Base(p))
+	var ack struct {
+		Duplicate bool   `json:"duplicate"`
+		Public    string `json:"public"`
 	}
-	return out
+	if json.NewDecoder(resp.Body).Decode(&ack) == nil && ack.Duplicate {
+		fmt.Printf("ℹ️  Host already had this report (%s); nothing new stored.\n", ack.Public)
+	}
+	return nil
 }
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// promMetric is one metric family in the text exposition format.
+type promMetric struct {
+	name, help string
+	samples    []string // `{labels} value` lines without the name
+}
+
+// promLabels renders k1, v1, k2, v2, … as {k1="v1",k2="v2"}, escaping values
+// as the exposition format requires.
+func promLabels(kv ...string) string {
+	esc := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(kv); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `%s="%s"`, kv[i], esc.Replace(kv[i+1]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func promValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func promBool(v bool) string {
+	return ternary(v, "1", "0")
+}
+
+// telemetryToPrometheus renders a report as Prometheus gauges, labelled by
+// machine, model and (for per-sample values) digit.
+func telemetryToPrometheus(report TelemetryReport) string {
+	return telemetryReportsToPrometheus([]TelemetryReport{report})
+}
+
+// telemetryReportsToPrometheus renders several reports (e.g. the latest per
+// machine) as one exposition, each family's HELP/TYPE written once.
+func telemetryReportsToPrometheus(reports []TelemetryReport) string {
+	fams := []*promMetric{
+		{name: "telemetry_run_timestamp_seconds", help: "End of the telemetry run (Unix time)."},
+		{name: "telemetry_gpu_init_ok", help: "1 if WebGPU initialized for the model."},
+		{name: "telemetry_gpu_init_ms", help: "WebGPU initialization time in milliseconds."},
+		{name: "telemetry_gpu_fallback", help: "1 if the model's GPU numbers are CPU fallback numbers."},
+		{name: "telemetry_top1_accuracy", help: "Top-1 accuracy over the fixed digit samples."},
+		{name: "telemetry_cpu_gpu_agree", help: "Digit samples where CPU and GPU predict the same class."},
+		{name: "telemetry_cpu_ms", help: "CPU forward time per digit sample in milliseconds."},
+		{name: "telemetry_gpu_ms", help: "GPU forward time per digit sample in milliseconds."},
+		{name: "telemetry_drift_mae", help: "Mean absolute CPU vs GPU output difference per digit sample."},
+		{name: "telemetry_drift_max_abs", help: "Largest CPU vs GPU output difference per digit sample."},
+		{name: "telemetry_gpu_warm_reused", help: "1 if --gpu-warm reused a resident WebGPU context, so no init was timed."},
+	}
+	add := func(i int, labels, v string) { fams[i].samples = append(fams[i].samples, labels+" "+v) }
+
+	for _, r := range reports {
+		machine := r.MachineID
+		add(0, promLabels("machine_id", machine, "source", string(r.Source)), promValue(float64(r.EndedAt.Unix())))
+		for _, mr := range r.PerModel {
+			m := promLabels("machine_id", machine, "model", mr.ModelFile)
+			add(1, m, promBool(mr.WebGPUInitOK))
+			if !mr.GPUWarmReused {
+				add(2, m, promValue(mr.WebGPUInitTimeMS))
+			}
+			add(10, m, promBool(mr.GPUWarmReused))
+			add(3, m, promBool(mr.GPUFallback))
+			add(4, promLabels("machine_id", machine, "model", mr.ModelFile, "device", "cpu"), promValue(mr.ADHD10.Top1AccuracyCPU))
+			add(4, promLabels("machine_id", machine, "model", mr.ModelFile, "device", "gpu"), promValue(mr.ADHD10.Top1AccuracyGPU))
+			add(5, m, strconv.Itoa(mr.ADHD10.CPUvsGPUAgreeCount))
+			digit := func(d int) string {
+				return promLabels("machine_id", machine, "model", mr.ModelFile, "digit", strconv.Itoa(d))
+			}
+			for _, t := range mr.CPU {
+				add(6, digit(t.Digit), promValue(t.ElapsedMS))
+			}
+			for _, t := range mr.GPU {
+				add(7, digit(t.Digit), promValue(t.ElapsedMS))
+			}
+			for _, d := range mr.Drift {
+				add(8, digit(d.Digit), promValue(d.MAE))
+				add(9, digit(d.Digit), promValue(d.MaxAbs))
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, f := range fams {
+		if len(f.samples) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", f.name, f.help, f.name)
+		for _, s := range f.samples {
+			b.WriteString(f.name + s + "\n")
+		}
+	}
+	return b.String()
+}
+
+// writeTelemetryProm writes telemetryToPrometheus(report) next to the report
+// at jsonPath (same name, .prom) — the format node_exporter's textfile
+// collector picks up — and returns its path.
+func writeTelemetryProm(jsonPath string, report TelemetryReport) (string, error) {
+	promPath := strings.TrimSuffix(jsonPath, filepath.Ext(jsonPath)) + ".prom"
+	if err := os.WriteFile(promPath, []byte(telemetryToPrometheus(report)), 0o644); err != nil {
+		return "", err
+	}
+	return promPath, nil
+}
+
+// latestReportsPerMachine reads the reports in dir and keeps the one with
+// the latest ended_at for each machine, ordered by machine ID. Files that
+// aren't valid telemetry JSON are skipped and counted.
+func latestReportsPerMachine(dir string) ([]TelemetryReport, int, error) {
+	list, err := listReports(dir)
+	if err != nil {
+		return nil, 0, err
+	}
+	type pick struct {
+		name  string
+		ended time.Time
+	}
+	latest := map[string]pick{}
+	skipped := 0
+	for _, r := range list {
+		b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(r.Name)))
+		if err != nil {
+			skipped++
+			continue
+		}
+		var head struct {
+			MachineID string    `json:"machine_id"`
+			EndedAt   time.Time `json:"ended_at"`
+		}
+		if err := json.Unmarshal(b, &head); err != nil || head.MachineID == "" {
+			skipped++
+			continue
+		}
+		if p, ok := latest[head.MachineID]; !ok || head.EndedAt.After(p.ended) {
+			latest[head.MachineID] = pick{r.Name, head.EndedAt}
+		}
+	}
+
+	ids := make([]string, 0, len(latest))
+	for id := range latest {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	out := make([]TelemetryReport, 0, len(ids))
+	for _, id := range ids {
+		b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(latest[id].name)))
+		if err != nil {
+			skipped++
+			continue
+		}
+		var rep TelemetryReport
+		if err := json.Unmarshal(b, &rep); err != nil {
+			skipped++
+			continue
+		}
+		out = append(out, rep)
+	}
+	return out, skipped, nil
+}
+
+// RegisterTelemetryMetrics mounts /metrics/telemetry: the latest uploaded
+// report of every machine in Prometheus text format, for scraping.
+func RegisterTelemetryMetrics(app *fiber.App, baseDir string) {
+	reportsDir := filepath.Join(baseDir, "reports")
+
+	app.Get("/metrics/telemetry", func(c *fiber.Ctx) error {
+		reports, skipped, err := latestReportsPerMachine(reportsDir)
+		if err != nil && !os.IsNotExist(err) {
+			return c.Status(fiber.StatusInternalServerError).SendString(err.Error())
+		}
+		if skipped > 0 {
+			c.Set("X-Reports-Skipped", strconv.Itoa(skipped))
+		}
+		c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+		return c.SendString(telemetryReportsToPrometheus(reports))
+	})
+}
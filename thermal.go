@@ -0,0 +1,153 @@
+package main
+
+import (
+	"flag"
+	"math"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --thermal samples CPU/GPU temperatures at the start and end of a telemetry
+// run, so a hot machine's slower numbers can be told apart from a cold one's.
+// Every source is best-effort; readings that need tools or privileges the
+// machine doesn't have are simply missing.
+var flagThermal = flag.Bool("thermal", false, "Record CPU/GPU temperatures at the start and end of telemetry runs (best-effort)")
+
+func thermalEnabled() bool {
+	return flag.Parsed() && *flagThermal
+}
+
+// ThermalReading is one sensor's temperature.
+type ThermalReading struct {
+	Sensor  string  `json:"sensor"` // e.g. "x86_pkg_temp", "gpu0", "CPU die"
+	Celsius float64 `json:"celsius"`
+}
+
+// ThermalSample is every reading taken at one moment.
+type ThermalSample struct {
+	At       time.Time        `json:"at"`
+	Source   string           `json:"source"` // sysfs | nvidia-smi | powermetrics | wmi | ohm, "+"-joined
+	Readings []ThermalReading `json:"readings"`
+}
+
+// sampleThermal reads whatever temperature sensors the OS exposes. It
+// returns nil when --thermal is off or nothing could be read.
+func sampleThermal() *ThermalSample {
+	if !thermalEnabled() {
+		return nil
+	}
+	s := &ThermalSample{At: time.Now().UTC()}
+	var sources []string
+	add := func(src string, rs []ThermalReading) {
+		if len(rs) > 0 {
+			s.Readings = append(s.Readings, rs...)
+			sources = append(sources, src)
+		}
+	}
+
+	switch runtime.GOOS {
+	case "linux":
+		add("sysfs", linuxThermalZones())
+		add("nvidia-smi", nvidiaTemps())
+	case "darwin":
+		// powermetrics needs root; -n keeps sudo from prompting.
+		add("powermetrics", powermetricsTemps(runOne("sudo", "-n", "powermetrics", "--samplers", "smc", "-i", "1", "-n", "1")))
+	case "windows":
+		add("ohm", ohmTemps(runOne("powershell", "-NoProfile",
+			`Get-CimInstance -Namespace root/OpenHardwareMonitor -ClassName Sensor | Where-Object SensorType -eq 'Temperature' | ForEach-Object { "$($_.Name)=$($_.Value)" }`)))
+		add("wmi", acpiTemps(runOne("wmic", "/namespace:\\\\root\\wmi", "PATH", "MSAcpi_ThermalZoneTemperature", "get", "CurrentTemperature")))
+		add("nvidia-smi", nvidiaTemps())
+	}
+	if len(s.Readings) == 0 {
+		return nil
+	}
+	s.Source = strings.Join(sources, "+")
+	return s
+}
+
+// linuxThermalZones reads /sys/class/thermal/thermal_zone*/{type,temp}
+// (millidegrees Celsius).
+func linuxThermalZones() []ThermalReading {
+	zones, _ := filepath.Glob("/sys/class/thermal/thermal_zone*")
+	var out []ThermalReading
+	for _, z := range zones {
+		milli, err := strconv.ParseFloat(strings.TrimSpace(readFile(filepath.Join(z, "temp"))), 64)
+		if err != nil || milli <= 0 {
+			continue
+		}
+		name := strings.TrimSpace(readFile(filepath.Join(z, "type")))
+		if name == "" {
+			name = filepath.Base(z)
+		}
+		out = append(out, ThermalReading{Sensor: name, Celsius: milli / 1000})
+	}
+	return out
+}
+
+// nvidiaTemps asks nvidia-smi for each GPU's core temperature.
+func nvidiaTemps() []ThermalReading {
+	var out []ThermalReading
+	for i, line := range strings.Split(runOne("nvidia-smi", "--query-gpu=temperature.gpu", "--format=csv,noheader,nounits"), "\n") {
+		if c, err := strconv.ParseFloat(strings.TrimSpace(line), 64); err == nil {
+			out = append(out, ThermalReading{Sensor: "gpu" + strconv.Itoa(i), Celsius: c})
+		}
+	}
+	return out
+}
+
+var powermetricsTempRe = regexp.MustCompile(`(?m)^(CPU|GPU) die temperature:\s*([0-9.]+)\s*C`)
+
+// powermetricsTemps parses "CPU die temperature: 52.31 C" lines.
+func powermetricsTemps(out string) []ThermalReading {
+	var rs []ThermalReading
+	for _, m := range powermetricsTempRe.FindAllStringSubmatch(out, -1) {
+		if c, err := strconv.ParseFloat(m[2], 64); err == nil {
+			rs = append(rs, ThermalReading{Sensor: m[1] + " die", Celsius: c})
+		}
+	}
+	return rs
+}
+
+// ohmTemps parses "name=value" lines from OpenHardwareMonitor's WMI sensors.
+func ohmTemps(out string) []ThermalReading {
+	var rs []ThermalReading
+	for _, line := range strings.Split(out, "\n") {
+		name, val, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		if c, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+			rs = append(rs, ThermalReading{Sensor: name, Celsius: c})
+		}
+	}
+	return rs
+}
+
+// acpiTemps parses MSAcpi_ThermalZoneTemperature (tenths of a Kelvin).
+func acpiTemps(out string) []ThermalReading {
+	var rs []ThermalReading
+	for _, line := range strings.Split(out, "\n") {
+		if dk, err := strconv.ParseFloat(strings.TrimSpace(line), 64); err == nil && dk > 0 {
+			rs = append(rs, ThermalReading{Sensor: "acpi" + strconv.Itoa(len(rs)), Celsius: math.Round((dk/10-273.15)*100) / 100})
+		}
+	}
+	return rs
+}
+
+// maxCelsius is the hottest reading in s, or 0 if there is none.
+func (s *ThermalSample) maxCelsius() float64 {
+	if s == nil {
+		return 0
+	}
+	var m float64
+	for _, r := range s.Readings {
+		if r.Celsius > m {
+			m = r.Celsius
+		}
+	}
+	return m
+}
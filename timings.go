@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Run-wide phase timings. Major operations add their elapsed time under a
+// phase name (load, init, train, eval, save); with --timings the totals are
+// dumped as JSON when the program finishes.
+var (
+	timingsMu     sync.Mutex
+	timings       = map[string]time.Duration{}
+	timingsCounts = map[string]int{}
+
+	flagTimings = flag.Bool("timings", false, "Dump a JSON breakdown of where the run spent its time (load/init/train/eval/save) on exit")
+)
+
+// PhaseTiming is one row of the --timings dump.
+type PhaseTiming struct {
+	Phase   string  `json:"phase"`
+	Count   int     `json:"count"`
+	TotalMS float64 `json:"total_ms"`
+}
+
+// recordTiming adds d to the running total for phase.
+func recordTiming(phase string, d time.Duration) {
+	timingsMu.Lock()
+	defer timingsMu.Unlock()
+	timings[phase] += d
+	timingsCounts[phase]++
+}
+
+// timingSnapshot returns the totals so far, ordered by phase name.
+func timingSnapshot() []PhaseTiming {
+	timingsMu.Lock()
+	defer timingsMu.Unlock()
+
+	out := make([]PhaseTiming, 0, len(timings))
+	for phase, d := range timings {
+		out = append(out, PhaseTiming{
+			Phase:   phase,
+			Count:   timingsCounts[phase],
+			TotalMS: float64(d.Microseconds()) / 1000.0,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Phase < out[j].Phase })
+	return out
+}
+
+// dumpTimings prints the breakdown as JSON when --timings is set.
+func dumpTimings() {
+	if !flag.Parsed() || !*flagTimings {
+		return
+	}
+	bz, _ := json.MarshalIndent(timingSnapshot(), "", "  ")
+	fmt.Println("⏱ Timings:")
+	fmt.Println(string(bz))
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"io"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -17,17 +19,10 @@ import (
 
 func runTrainMenu() {
 	reader := bufio.NewReader(os.Stdin)
-	modelDir := MustPublicPath("models")
+	modelDir := ModelsDir()
 
 	// Build model list
-	entries, _ := os.ReadDir(modelDir)
-	models := []string{}
-	for _, e := range entries {
-		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") || e.Name() == "manifest.json" {
-			continue
-		}
-		models = append(models, e.Name())
-	}
+	models, _ := listModels(modelDir)
 	if len(models) == 0 {
 		fmt.Println("❌ No models found in public/models/")
 		return
@@ -53,7 +48,7 @@ func runTrainMenu() {
 	if mode == "1" {
 		fmt.Println("\nAvailable models:")
 		for i, m := range models {
-			fmt.Printf("%d) %s\n", i+1, m)
+			fmt.Printf("%d) %s%s\n", i+1, m, modelChoiceLabel(modelDir, m))
 		}
 		fmt.Println("0) Back")
 		fmt.Print("Select model: ")
@@ -97,6 +92,16 @@ func runTrainMenu() {
 		}
 	}
 
+	smoothing := 0.0
+	fmt.Printf("Label smoothing ε [default %.2f, 0 = hard one-hot]: ", smoothing)
+	if s, _ := reader.ReadString('\n'); strings.TrimSpace(s) != "" {
+		if v, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil && v >= 0 && v < 1 {
+			smoothing = v
+		} else {
+			fmt.Println("⚠️  Invalid ε (want 0 ≤ ε < 1), using 0")
+		}
+	}
+
 	var epochs int
 	var target float64
 	var maxEpochs int
@@ -140,9 +145,9 @@ func runTrainMenu() {
 
 		var err error
 		if strat == "1" {
-			err = trainModelEpochs(modelPath, epochs, lr)
+			err = trainModelEpochs(modelPath, epochs, lr, smoothing)
 		} else {
-			err = trainModelUntilScore(modelPath, target, maxEpochs, lr)
+			err = trainModelUntilScore(modelPath, target, maxEpochs, lr, smoothing)
 		}
 		if err != nil {
 			fmt.Printf("   ❌ %s: %v\n", name, err)
@@ -174,53 +179,81 @@ func loadFloat32Model(modelPath string) (*paragon.Network[float32], error) {
 	if err != nil {
 		return nil, fmt.Errorf("load failed: %w", err)
 	}
-	tmp, ok := loaded.(*paragon.Network[float32])
-	if !ok {
-		return nil, fmt.Errorf("not float32: %T", loaded)
-	}
-
-	shapes := make([]struct{ Width, Height int }, len(tmp.Layers))
-	acts := make([]string, len(tmp.Layers))
-	trains := make([]bool, len(tmp.Layers))
-	for i, L := range tmp.Layers {
-		shapes[i] = struct{ Width, Height int }{L.Width, L.Height}
-		a := "linear"
-		if L.Height > 0 && L.Width > 0 && L.Neurons[0][0] != nil {
-			a = L.Neurons[0][0].Activation
-		}
-		acts[i], trains[i] = a, true
-	}
-	nn, err := paragon.NewNetwork[float32](shapes, acts, trains)
+	nn, err := rebuildFloat32(loaded)
 	if err != nil {
-		return nil, fmt.Errorf("NewNetwork failed: %w", err)
-	}
-	state, _ := tmp.MarshalJSONModel()
-	if err := nn.UnmarshalJSONModel(state); err != nil {
-		return nil, fmt.Errorf("UnmarshalJSONModel failed: %w", err)
+		return nil, err
 	}
+	warnTrainFlagMismatch(modelPath, layerTrainFlags(nn))
 	return nn, nil
 }
 
 // quiet ADHD score: no printing
 func evalADHDScore[T paragon.Numeric](nn *paragon.Network[T], inputs, targets [][][]float64) float64 {
+	score, _ := evalADHDScoreLoss(nn, inputs, targets)
+	return score
+}
+
+// evalADHDScoreLoss is evalADHDScore plus the --loss metric over the same
+// forward passes (0 when no metric is selected).
+func evalADHDScoreLoss[T paragon.Numeric](nn *paragon.Network[T], inputs, targets [][][]float64) (score, loss float64) {
+	start := time.Now()
+	defer func() { recordTiming("eval", time.Since(start)) }()
 	expected := make([]float64, len(inputs))
 	actual := make([]float64, len(inputs))
+	acc := newLossAccum(lossMetric())
 	for i := range inputs {
 		nn.Forward(inputs[i])
 		out := nn.ExtractOutput()
+		acc.add(out, targets[i][0])
 		expected[i] = float64(paragon.ArgMax(targets[i][0]))
 		actual[i] = float64(paragon.ArgMax(out))
 	}
 	nn.EvaluateModel(expected, actual)
-	return nn.Performance.Score
+	return nn.Performance.Score, acc.value()
+}
+
+// --cpu-only skips WebGPU entirely in train/evaluate/compare/benchmark, so a
+// quick CPU score doesn't pay seconds of GPU init first.
+var flagCPUOnly = flag.Bool("cpu-only", false, "Skip WebGPU init everywhere and run pure CPU forwards")
+
+func cpuOnly() bool {
+	return flag.Parsed() && *flagCPUOnly
+}
+
+// --augment trains on randomly shifted/rotated copies of the training inputs,
+// re-drawn every epoch. Test inputs are never augmented.
+var flagAugment = flag.Bool("augment", false, "Augment MNIST training inputs (random shift/rotate, new draw each epoch)")
+
+func augmentEnabled() bool {
+	return flag.Parsed() && *flagAugment
+}
+
+// epochInputs returns the inputs to train on for epoch ep (1-based).
+func epochInputs(trainInputs [][][]float64, ep int) [][][]float64 {
+	if !augmentEnabled() {
+		return trainInputs
+	}
+	return augmentSet(trainInputs, time.Now().UnixNano()+int64(ep)*int64(len(trainInputs)))
 }
 
 func withGPU[T paragon.Numeric](nn *paragon.Network[T], warm [][][]float64) (cleanup func(), used bool) {
+	if cpuOnly() {
+		fmt.Println("ℹ️  --cpu-only: skipping WebGPU init.")
+		nn.WebGPUNative = false
+		return func() {}, false
+	}
+	if err := requireWebGPU(); err != nil {
+		warnGPUFallback("training", err)
+		nn.WebGPUNative = false
+		return func() {}, false
+	}
 	nn.WebGPUNative = true
 	nn.Debug = false
 	start := time.Now()
-	if err := nn.InitializeOptimizedGPU(); err != nil {
-		fmt.Printf("⚠️  WebGPU init failed: %v\n   Falling back to CPU.\n", err)
+	err := nn.InitializeOptimizedGPU()
+	recordTiming("init", time.Since(start))
+	if err != nil {
+		warnGPUFallback("training", err)
 		nn.WebGPUNative = false
 		return func() {}, false
 	}
@@ -232,12 +265,54 @@ func withGPU[T paragon.Numeric](nn *paragon.Network[T], warm [][][]float64) (cle
 	return func() { nn.CleanupOptimizedGPU() }, true
 }
 
-func trainModelEpochs(modelPath string, epochs int, lr float64) error {
-	images, labels, err := loadMNISTData(MustPublicPath("mnist"))
+// smoothTargets applies label smoothing to one-hot targets: the true class
+// becomes 1-ε and every other class ε/(n-1) (ε/9 for MNIST). It returns new
+// slices, so the cached MNIST labels are left untouched. ε == 0 is a no-op.
+func smoothTargets(targets [][][]float64, eps float64) [][][]float64 {
+	if eps <= 0 {
+		return targets
+	}
+	out := make([][][]float64, len(targets))
+	for i, t := range targets {
+		row := t[0]
+		n := len(row)
+		s := make([]float64, n)
+		if n > 1 {
+			hot := paragon.ArgMax(row)
+			for j := range s {
+				s[j] = eps / float64(n-1)
+			}
+			s[hot] = 1 - eps
+		} else {
+			copy(s, row)
+		}
+		out[i] = [][]float64{s}
+	}
+	return out
+}
+
+// trainEpochEvent is the --ndjson record for a finished epoch or run. Scores
+// are omitted when the epoch wasn't evaluated.
+type trainEpochEvent struct {
+	Model      string  `json:"model"`
+	Epoch      int     `json:"epoch"`
+	TrainScore float64 `json:"train_score,omitempty"`
+	TestScore  float64 `json:"test_score,omitempty"`
+	TrainLoss  float64 `json:"train_loss,omitempty"` // --loss
+	TestLoss   float64 `json:"test_loss,omitempty"`
+	ElapsedMS  float64 `json:"elapsed_ms"`
+}
+
+func msSince(t time.Time) float64 {
+	return float64(time.Since(t).Microseconds()) / 1000.0
+}
+
+func trainModelEpochs(modelPath string, epochs int, lr, smoothing float64) error {
+	images, labels, err := getMNIST()
 	if err != nil {
 		return fmt.Errorf("load MNIST: %w", err)
 	}
-	trainInputs, trainTargets, testInputs, testTargets := paragon.SplitDataset(images, labels, 0.8)
+	trainInputs, trainTargets, testInputs, testTargets := splitMNIST(images, labels)
 
 	nn, err := loadFloat32Model(modelPath)
 	if err != nil {
@@ -247,30 +322,54 @@ func trainModelEpochs(modelPath string, epochs int, lr float64) error {
 	cleanup, _ := withGPU(nn, trainInputs)
 	defer cleanup()
 
-	fmt.Printf("🧠 Training %s for %d epoch(s) @ lr=%.4f …\n", filepath.Base(modelPath), epochs, lr)
+	trainInputs, trainTargets = maybeBalance(trainInputs, trainTargets)
+	fitTargets := smoothTargets(trainTargets, smoothing)
+
+	fmt.Printf("🧠 Training %s for %d epoch(s) @ lr=%.4f ε=%.2f augment=%v split=%s …\n",
+		filepath.Base(modelPath), epochs, lr, smoothing, augmentEnabled(), splitLabel())
 	start := time.Now()
 	//withSilencedStdout(func() {
-	nn.Train(trainInputs, trainTargets, epochs, lr, false, float32(2), float32(-2))
+	if augmentEnabled() || ndjsonEnabled() {
+		// one Train call per epoch so each epoch sees a fresh augmentation
+		// and --ndjson can report it as it finishes
+		for ep := 1; ep <= epochs; ep++ {
+			epStart := time.Now()
+			nn.Train(epochInputs(trainInputs, ep), fitTargets, 1, lr, false, float32(2), float32(-2))
+			emitEvent("train_epoch", trainEpochEvent{
+				Model: filepath.Base(modelPath), Epoch: ep, ElapsedMS: msSince(epStart),
+			})
+		}
+	} else {
+		nn.Train(trainInputs, fitTargets, epochs, lr, false, float32(2), float32(-2))
+	}
 	//})
 	fmt.Printf("⏱ Training time: %v\n", time.Since(start))
-
-	trainScore := evalADHDScore(nn, trainInputs, trainTargets)
-	testScore := evalADHDScore(nn, testInputs, testTargets)
-	fmt.Printf("🎯 ADHD scores → Train: %.4f%% | Test: %.4f%%\n", trainScore, testScore)
-
-	if err := nn.SaveJSON(modelPath); err != nil {
+	recordTiming("train", time.Since(start))
+
+	trainScore, trainLoss := evalADHDScoreLoss(nn, trainInputs, trainTargets)
+	testScore, testLoss := evalADHDScoreLoss(nn, testInputs, testTargets)
+	fmt.Printf("🎯 ADHD scores → Train: %.4f%% | Test: %.4f%%%s\n", trainScore, testScore, formatLosses(trainLoss, testLoss))
+	emitEvent("train_done", trainEpochEvent{
+		Model: filepath.Base(modelPath), Epoch: epochs, TrainScore: trainScore, TestScore: testScore,
+		TrainLoss: trainLoss, TestLoss: testLoss, ElapsedMS: msSince(start),
+	})
+
+	saveStart := time.Now()
+	if err := saveModel(nn, modelPath); err != nil {
 		return fmt.Errorf("save model: %w", err)
 	}
+	recordTiming("save", time.Since(saveStart))
 	fmt.Printf("💾 Saved → %s\n", modelPath)
+	recordTraining(modelPath, testScore)
 	return nil
 }
 
-func trainModelUntilScore(modelPath string, targetPct float64, maxEpochs int, lr float64) error {
-	images, labels, err := loadMNISTData(MustPublicPath("mnist"))
+func trainModelUntilScore(modelPath string, targetPct float64, maxEpochs int, lr, smoothing float64) error {
+	images, labels, err := getMNIST()
 	if err != nil {
 		return fmt.Errorf("load MNIST: %w", err)
 	}
-	trainInputs, trainTargets, testInputs, testTargets := paragon.SplitDataset(images, labels, 0.8)
+	trainInputs, trainTargets, testInputs, testTargets := splitMNIST(images, labels)
 
 	nn, err := loadFloat32Model(modelPath)
 	if err != nil {
@@ -280,28 +379,37 @@ func trainModelUntilScore(modelPath string, targetPct float64, maxEpochs int, lr
 	cleanup, _ := withGPU(nn, trainInputs)
 	defer cleanup()
 
-	fmt.Printf("🧠 Training %s until ADHD ≥ %.2f%% (max %d epochs) @ lr=%.4f …\n",
-		filepath.Base(modelPath), targetPct, maxEpochs, lr)
+	trainInputs, trainTargets = maybeBalance(trainInputs, trainTargets)
+	fitTargets := smoothTargets(trainTargets, smoothing)
+
+	fmt.Printf("🧠 Training %s until ADHD ≥ %.2f%% (max %d epochs) @ lr=%.4f ε=%.2f augment=%v split=%s …\n",
+		filepath.Base(modelPath), targetPct, maxEpochs, lr, smoothing, augmentEnabled(), splitLabel())
 
 	startAll := time.Now()
-	best := -1.0
+	best, last := -1.0, 0.0
 	var hitEpoch int = -1
 
 	for ep := 1; ep <= maxEpochs; ep++ {
 		epStart := time.Now()
 		//withSilencedStdout(func() {
-		nn.Train(trainInputs, trainTargets, 1, lr, false, float32(2), float32(-2))
+		nn.Train(epochInputs(trainInputs, ep), fitTargets, 1, lr, false, float32(2), float32(-2))
 		//})
 		epDur := time.Since(epStart)
+		recordTiming("train", epDur)
 
-		trainScore := evalADHDScore(nn, trainInputs, trainTargets)
-		testScore := evalADHDScore(nn, testInputs, testTargets)
+		trainScore, trainLoss := evalADHDScoreLoss(nn, trainInputs, trainTargets)
+		testScore, testLoss := evalADHDScoreLoss(nn, testInputs, testTargets)
+		last = testScore
 		if testScore > best {
 			best = testScore
 		}
 
-		fmt.Printf("   Epoch %2d: Train=%.4f%%  Test=%.4f%% (best=%.4f%%)  ⏱ %v\n",
-			ep, trainScore, testScore, best, epDur)
+		fmt.Printf("   Epoch %2d: Train=%.4f%%  Test=%.4f%% (best=%.4f%%)%s  ⏱ %v\n",
+			ep, trainScore, testScore, best, formatLosses(trainLoss, testLoss), epDur)
+		emitEvent("train_epoch", trainEpochEvent{
+			Model: filepath.Base(modelPath), Epoch: ep, TrainScore: trainScore, TestScore: testScore,
+			TrainLoss: trainLoss, TestLoss: testLoss, ElapsedMS: float64(epDur.Microseconds()) / 1000.0,
+		})
 
 		if testScore >= targetPct {
 			hitEpoch = ep
@@ -316,9 +424,112 @@ func trainModelUntilScore(modelPath string, targetPct float64, maxEpochs int, lr
 		fmt.Printf("⚠️  Target not reached (best Test=%.4f%% after %d epochs)\n", best, maxEpochs)
 	}
 
-	if err := nn.SaveJSON(modelPath); err != nil {
+	saveStart := time.Now()
+	if err := saveModel(nn, modelPath); err != nil {
 		return fmt.Errorf("save model: %w", err)
 	}
+	recordTiming("save", time.Since(saveStart))
 	fmt.Printf("💾 Saved → %s\n", modelPath)
+	// the saved weights are the final epoch's, so record that score
+	recordTraining(modelPath, last)
 	return nil
 }
+
+// --balance oversamples minority classes in the training set until every
+// class has as many samples as the largest one. MNIST is close to balanced,
+// so this mainly matters for custom datasets. Test sets are never touched.
+var flagBalance = flag.Bool("balance", false, "Oversample minority classes in the training set to match the largest class")
+
+func balanceEnabled() bool {
+	return flag.Parsed() && *flagBalance
+}
+
+// balanceSeed keeps --balance runs reproducible.
+const balanceSeed = 42
+
+// classCounts tallies one-hot targets by class.
+func classCounts(targets [][][]float64) []int {
+	var counts []int
+	for _, t := range targets {
+		c := paragon.ArgMax(t[0])
+		for len(counts) <= c {
+			counts = append(counts, 0)
+		}
+		counts[c]++
+	}
+	return counts
+}
+
+// classWeights returns inverse-frequency weights total/(classes·count) per
+// class, so a perfectly balanced set gives 1.0 everywhere. Classes with no
+// samples get 0.
+func classWeights(targets [][][]float64) []float64 {
+	counts := classCounts(targets)
+	w := make([]float64, len(counts))
+	for c, n := range counts {
+		if n > 0 {
+			w[c] = float64(len(targets)) / (float64(len(counts)) * float64(n))
+		}
+	}
+	return w
+}
+
+// balanceTrainingSet oversamples (with replacement) every class up to the
+// size of the largest one and shuffles the result. It returns new slices that
+// share the underlying samples, so the cached MNIST data is left untouched.
+func balanceTrainingSet(inputs, targets [][][]float64, seed int64) ([][][]float64, [][][]float64) {
+	var byClass [][]int
+	most := 0
+	for i, t := range targets {
+		c := paragon.ArgMax(t[0])
+		for len(byClass) <= c {
+			byClass = append(byClass, nil)
+		}
+		byClass[c] = append(byClass[c], i)
+		most = max(most, len(byClass[c]))
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	order := make([]int, 0, most*len(byClass))
+	for _, idx := range byClass {
+		if len(idx) == 0 {
+			continue
+		}
+		order = append(order, idx...)
+		for k := len(idx); k < most; k++ {
+			order = append(order, idx[rng.Intn(len(idx))])
+		}
+	}
+	if len(order) == len(inputs) {
+		return inputs, targets
+	}
+	rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+	outIn := make([][][]float64, len(order))
+	outTarg := make([][][]float64, len(order))
+	for k, i := range order {
+		outIn[k], outTarg[k] = inputs[i], targets[i]
+	}
+	return outIn, outTarg
+}
+
+// maybeBalance applies balanceTrainingSet when --balance is set and prints
+// the class weights it was correcting for.
+func maybeBalance(inputs, targets [][][]float64) ([][][]float64, [][][]float64) {
+	if !balanceEnabled() {
+		return inputs, targets
+	}
+	w := classWeights(targets)
+	parts := make([]string, len(w))
+	for c, v := range w {
+		parts[c] = fmt.Sprintf("%d:%.2f", c, v)
+	}
+	fmt.Printf("⚖️  Class weights before balancing: %s\n", strings.Join(parts, " "))
+	balIn, balTarg := balanceTrainingSet(inputs, targets, balanceSeed)
+	if len(balIn) == len(inputs) {
+		fmt.Println("⚖️  Training set is already balanced.")
+	} else {
+		fmt.Printf("⚖️  Oversampled training set: %d → %d samples\n", len(inputs), len(balIn))
+	}
+	return balIn, balTarg
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Build provenance, set at build time:
+//
+//	go build -ldflags "-X main.Version=v1.4.0 -X main.Commit=abc1234 -X main.BuildTime=2025-01-01T00:00:00Z" .
+//
+// build_all.sh fills them from git and the clock. Unstamped builds report "dev".
+var (
+	Version   = "dev"
+	Commit    = "dev"
+	BuildTime = "dev"
+)
+
+// BuildInfo is the provenance block embedded in /whoami, SystemInfo and
+// telemetry reports, so any report can be traced to the build that made it.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+}
+
+func currentBuild() BuildInfo {
+	return BuildInfo{Version: Version, Commit: Commit, BuildTime: BuildTime}
+}
+
+// fetchHostVersion asks a model host for its build version (GET /version).
+func fetchHostVersion(hostBase string) (string, error) {
+	u := strings.TrimRight(hostBase, "/") + "/version"
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := telemetryGet(client, u)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status %s from %s", resp.Status, u)
+	}
+	var body struct {
+		Version string `json:"version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	return body.Version, nil
+}
+
+// checkHostVersion warns when the host runs a newer build than this client.
+// It never fails the caller: old hosts without /version are simply skipped.
+func checkHostVersion(hostBase string) {
+	hostVer, err := fetchHostVersion(hostBase)
+	if err != nil {
+		fmt.Printf("ℹ️  Host version unknown (%v)\n", err)
+		return
+	}
+	cmp, ok := compareVersions(hostVer, Version)
+	switch {
+	case !ok:
+		fmt.Printf("ℹ️  Host version %s, client %s (not comparable)\n", hostVer, Version)
+	case cmp > 0:
+		fmt.Printf("⚠️  Host runs %s but this client is %s — grab a fresh binary from %s/compiled/\n",
+			hostVer, Version, strings.TrimRight(hostBase, "/"))
+	default:
+		fmt.Printf("✅ Client %s is up to date with host %s\n", Version, hostVer)
+	}
+}
+
+// compareVersions compares dotted numeric versions ("v1.2.3", "1.10"),
+// ignoring any "-suffix" (e.g. git describe's "-3-gabc123"). ok is false when
+// either side isn't a plain version, such as "dev".
+func compareVersions(a, b string) (cmp int, ok bool) {
+	pa, okA := parseVersion(a)
+	pb, okB := parseVersion(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x > y {
+				return 1, true
+			}
+			return -1, true
+		}
+	}
+	return 0, true
+}
+
+func parseVersion(v string) ([]int, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	if v == "" {
+		return nil, false
+	}
+	var out []int
+	for _, part := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		out = append(out, n)
+	}
+	return out, true
+}
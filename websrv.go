@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"net"
 	"os"
@@ -11,25 +12,72 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/compress"
+	compressmw "github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
 type webServer struct {
-	app     *fiber.App
-	addr    string
-	dir     string
-	running bool
-	mu      sync.RWMutex
-	errc    chan error
+	app       *fiber.App
+	addr      string
+	dir       string
+	running   bool
+	startedAt time.Time
+	mu        sync.RWMutex
+	errc      chan error
 }
 
 var ws webServer
 
+// --web-compress=false turns off response compression, e.g. to inspect
+// downloads on the wire or spare the host CPU on already-dense model JSON.
+var flagWebCompress = flag.Bool("web-compress", true, "Compress web server responses (set false to debug downloads or save host CPU)")
+
+func webCompressDefault() bool {
+	return !flag.Parsed() || *flagWebCompress
+}
+
+// --bind picks the interface the server listens on. The default 0.0.0.0
+// reaches every network, VPNs and docker bridges included; 127.0.0.1 keeps it
+// on this machine, a LAN IP on that LAN.
+var flagBind = flag.String("bind", "0.0.0.0", "Web server bind address: 0.0.0.0 (all interfaces), 127.0.0.1 (this machine only) or one of this machine's IPs")
+
+func webBindDefault() string {
+	if flag.Parsed() && strings.TrimSpace(*flagBind) != "" {
+		return strings.TrimSpace(*flagBind)
+	}
+	return "0.0.0.0"
+}
+
+// checkBindAddr accepts an unspecified address (0.0.0.0, ::), "localhost" or
+// an IP assigned to one of this machine's interfaces, and returns it as an IP
+// string. Listen would fail on anything else, but only inside the server
+// goroutine, after the menu had already reported success.
+func checkBindAddr(bind string) (string, error) {
+	if bind == "" || bind == "localhost" {
+		return ternary(bind == "", "0.0.0.0", "127.0.0.1"), nil
+	}
+	ip := net.ParseIP(bind)
+	if ip == nil {
+		return "", fmt.Errorf("bind address %q is not an IP address", bind)
+	}
+	if ip.IsUnspecified() || ip.IsLoopback() {
+		return ip.String(), nil
+	}
+	addrs, _ := net.InterfaceAddrs()
+	for _, a := range addrs {
+		if ipnet, ok := a.(*net.IPNet); ok && ipnet.IP.Equal(ip) {
+			return ip.String(), nil
+		}
+	}
+	return "", fmt.Errorf("bind address %s is not assigned to any interface on this machine", bind)
+}
+
 // StartWeb starts a Fiber server in a goroutine and serves `dir` at `/`,
-// and `dir/compiled` at `/compiled`. Binds 0.0.0.0 so your LAN can reach it.
-func StartWeb(port int, dir string) error {
+// and `dir/compiled` at `/compiled`. bindAddr is the interface to listen on
+// (see --bind; "" means 0.0.0.0, every interface).
+// compress installs the compress middleware (LevelBestSpeed).
+func StartWeb(bindAddr string, port int, dir string, compress bool) error {
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
 
@@ -40,10 +88,23 @@ func StartWeb(port int, dir string) error {
 		dir = "public"
 	}
 	if _, err := os.Stat(dir); err != nil {
-		return fmt.Errorf("public dir %q not found: %w", dir, err)
+		// The menu default "public" is relative to the working directory;
+		// fall back to the resolved data dir (seeded with embedded defaults).
+		if dir != "public" {
+			return fmt.Errorf("public dir %q not found: %w", dir, err)
+		}
+		base, berr := BaseDir()
+		if berr != nil {
+			return fmt.Errorf("public dir %q not found: %w", dir, err)
+		}
+		dir = base
 	}
 
-	ws.addr = fmt.Sprintf("0.0.0.0:%d", port)
+	bind, err := checkBindAddr(bindAddr)
+	if err != nil {
+		return err
+	}
+	ws.addr = net.JoinHostPort(bind, strconv.Itoa(port))
 	ws.dir = dir
 	ws.errc = make(chan error, 1)
 
@@ -62,17 +123,31 @@ func StartWeb(port int, dir string) error {
 		AllowOrigins: "*",
 		AllowHeaders: "*",
 	}))
-	app.Use(compress.New(compress.Config{Level: compress.LevelBestSpeed}))
+	if compress {
+		app.Use(compressmw.New(compressmw.Config{Level: compressmw.LevelBestSpeed}))
+	}
+	app.Use(func(c *fiber.Ctx) error {
+		webMetrics.requests.Add(1)
+		return c.Next()
+	})
 
 	RegisterUpload(app, ws.dir)
+	RegisterDashboard(app, ws.dir)
+	RegisterTelemetryMetrics(app, ws.dir)
+	RegisterInfer(app, ws.dir)
 
 	// Health/info
 	app.Get("/healthz", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	app.Get("/version", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"version": Version})
+	})
 	app.Get("/whoami", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
+			"version":    Version,
+			"build":      currentBuild(),
 			"addr":       ws.addr,
 			"public_dir": filepath.Clean(ws.dir),
-			"lan_urls":   lanURLs(port),
+			"lan_urls":   lanURLs(bind, port),
 			"started_at": time.Now().UTC(),
 		})
 	})
@@ -100,8 +175,13 @@ func StartWeb(port int, dir string) error {
 	// Mark running
 	ws.app = app
 	ws.running = true
-	printServerBanner(port, dir)
-	printCompiledIndex(port, dir)
+	ws.startedAt = time.Now()
+	printServerBanner(bind, port, dir)
+	if !compress {
+		fmt.Println("ℹ️  Response compression is off.")
+	}
+	printCompiledIndex(bind, port, dir)
+	fmt.Printf("📊 Dashboard: %s/dashboard\n", localURL(bind, port))
 
 	return nil
 }
@@ -135,7 +215,13 @@ func WebStatus() (bool, string) {
 
 // ---- helpers ----
 
-func lanURLs(port int) []string {
+// lanURLs lists the URLs the server is reachable at for its bind address:
+// every interface's IPv4 plus loopback when bound to all interfaces, only
+// the loopback URL when bound to loopback, and the one IP otherwise.
+func lanURLs(bind string, port int) []string {
+	if ip := net.ParseIP(bind); ip != nil && !ip.IsUnspecified() {
+		return []string{localURL(bind, port)}
+	}
 	var urls []string
 	ifaces, _ := net.Interfaces()
 	for _, ifc := range ifaces {
@@ -149,16 +235,26 @@ func lanURLs(port int) []string {
 			}
 		}
 	}
-	urls = append(urls, fmt.Sprintf("http://127.0.0.1:%d", port))
+	urls = append(urls, localURL(bind, port))
 	return urls
 }
 
-func printServerBanner(port int, dir string) {
+// localURL is the URL to reach the server from this machine: loopback
+// unless it is bound to one specific IP.
+func localURL(bind string, port int) string {
+	ip := net.ParseIP(bind)
+	if ip == nil || ip.IsUnspecified() {
+		return fmt.Sprintf("http://127.0.0.1:%d", port)
+	}
+	return "http://" + net.JoinHostPort(ip.String(), strconv.Itoa(port))
+}
+
+func printServerBanner(bind string, port int, dir string) {
 	absDir, _ := filepath.Abs(dir)
 	compiledDir := filepath.Join(absDir, "compiled")
 
 	fmt.Println("🌐 Web server started")
-	for _, u := range lanURLs(port) {
+	for _, u := range lanURLs(bind, port) {
 		fmt.Printf(" → %s\n", u)
 	}
 	fmt.Printf(" Serving: %s\n", absDir)
@@ -215,17 +311,14 @@ func collectCompiledFiles(dir string) []string {
 
 // printCompiledIndex prints per-LAN-URL links for each compiled artifact,
 // plus a ready-to-paste curl line using the first LAN URL (or localhost).
-func printCompiledIndex(port int, dir string) {
+func printCompiledIndex(bind string, port int, dir string) {
 	files := collectCompiledFiles(dir)
 	if len(files) == 0 {
 		fmt.Println("ℹ️  No files found in ./public/compiled (nothing to index).")
 		return
 	}
 
-	urls := lanURLs(port)
-	if len(urls) == 0 {
-		urls = []string{fmt.Sprintf("http://127.0.0.1:%d", port)}
-	}
+	urls := lanURLs(bind, port)
 
 	fmt.Println("📦 Compiled artifacts:")
 	for _, u := range urls {
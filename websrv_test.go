@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// freePort asks the kernel for an unused TCP port.
+func freePort(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+// httpGetBody GETs url and returns the body, failing on non-200.
+func httpGetBody(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return body, nil
+}
+
+// postUpload sends body to /upload as a multipart file stored under name
+// and returns the response status.
+func postUpload(t *testing.T, client *http.Client, base, name string, body []byte) int {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	fw, err := w.CreateFormFile("file", "upload.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write(body)
+	w.WriteField("name", name)
+	w.Close()
+	resp, err := client.Post(base+"/upload", w.FormDataContentType(), &buf)
+	if err != nil {
+		t.Fatalf("/upload: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// TestWebLifecycle runs the server end to end on a free port: /healthz,
+// /whoami, an /upload fetched back from /reports/ (and one whose name tries
+// to leave the reports dir), then StopWeb and a check
+// that the port was released.
+func TestWebLifecycle(t *testing.T) {
+	port := freePort(t)
+	dir := t.TempDir()
+	if err := StartWeb("127.0.0.1", port, dir, false); err != nil {
+		t.Fatalf("StartWeb: %v", err)
+	}
+	stopped := false
+	t.Cleanup(func() {
+		if !stopped {
+			_ = StopWeb()
+		}
+	})
+
+	base := fmt.Sprintf("http://127.0.0.1:%d", port)
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	// the listener comes up in a goroutine, so give it a moment
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		body, err := httpGetBody(client, base+"/healthz")
+		if err == nil {
+			if strings.TrimSpace(string(body)) != "ok" {
+				t.Fatalf("/healthz body %q", body)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("/healthz: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	body, err := httpGetBody(client, base+"/whoami")
+	if err != nil {
+		t.Fatalf("/whoami: %v", err)
+	}
+	var who struct {
+		Version string `json:"version"`
+		Addr    string `json:"addr"`
+	}
+	if err := json.Unmarshal(body, &who); err != nil {
+		t.Fatalf("/whoami: %v", err)
+	}
+	if who.Version != Version || parsePort(who.Addr) != port {
+		t.Fatalf("/whoami: version=%q addr=%q", who.Version, who.Addr)
+	}
+
+	want := []byte(`{"webtest":true}`)
+	name := fmt.Sprintf("webtest_%d.json", time.Now().UnixNano())
+	if status := postUpload(t, client, base, name, want); status != http.StatusOK {
+		t.Fatalf("/upload: status %d", status)
+	}
+
+	// a name with directories in it is cut down to its base name
+	escape := fmt.Sprintf("escape_%d.json", time.Now().UnixNano())
+	if status := postUpload(t, client, base, "../../"+escape, []byte(`{"escape":true}`)); status != http.StatusOK {
+		t.Fatalf("/upload ../: status %d", status)
+	}
+	for _, p := range []string{filepath.Join(dir, escape), filepath.Join(filepath.Dir(dir), escape)} {
+		if _, err := os.Stat(p); err == nil {
+			t.Fatalf("upload escaped the reports dir: %s", p)
+		}
+	}
+	if status := postUpload(t, client, base, "..", want); status != http.StatusBadRequest {
+		t.Fatalf("/upload with name \"..\": status %d, want 400", status)
+	}
+
+	// with REPORTS_PARTITION=date the report lands in a dated subdir
+	list, err := listReports(filepath.Join(dir, "reports"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, escaped := "", false
+	for _, r := range list {
+		switch path.Base(r.Name) {
+		case name:
+			stored = r.Name
+		case escape:
+			escaped = true
+		}
+	}
+	if stored == "" || !escaped {
+		t.Fatalf("%s or %s not among the stored reports %v", name, escape, list)
+	}
+	got, err := httpGetBody(client, base+"/reports/"+escapeReportPath(stored))
+	if err != nil {
+		t.Fatalf("fetch back: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("fetched %q, want %q", got, want)
+	}
+
+	stopped = true
+	if err := StopWeb(); err != nil {
+		t.Fatalf("StopWeb: %v", err)
+	}
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		t.Fatalf("port %d still in use after StopWeb: %v", port, err)
+	}
+	ln.Close()
+}
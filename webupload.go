@@ -1,14 +1,64 @@
 package main
 
 import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// maxUploadInflated caps how large a gzip upload may decompress to, so a
+// tiny gzip bomb can't fill the host's disk.
+const maxUploadInflated = 512 << 20
+
+var errBadUpload = errors.New("bad upload")
+
+// saveGunzipped decompresses a gzip multipart file into dst, via a temp file
+// so a truncated or oversized upload never leaves a partial report behind.
+func saveGunzipped(fh *multipart.FileHeader, dst string) error {
+	src, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	zr, err := gzip.NewReader(src)
+	if err != nil {
+		return fmt.Errorf("%w: not gzip: %v", errBadUpload, err)
+	}
+	defer zr.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	n, err := io.Copy(tmp, io.LimitReader(zr, maxUploadInflated+1))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("%w: gunzip: %v", errBadUpload, err)
+	}
+	if n > maxUploadInflated {
+		return fmt.Errorf("%w: decompresses past %d MB", errBadUpload, maxUploadInflated>>20)
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
 func RegisterUpload(app *fiber.App, baseDir string) {
 	reportsDir := filepath.Join(baseDir, "reports")
 
@@ -37,22 +87,219 @@ func RegisterUpload(app *fiber.App, baseDir string) {
 		if name == "" {
 			name = fmt.Sprintf("%d_%s", time.Now().Unix(), fh.Filename)
 		}
+		// only a bare file name: never let the client pick a directory
+		name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+		if name == "." || name == ".." || name == "/" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid report name",
+			})
+		}
+
+		// gzip uploads (Content-Encoding: gzip on the part, or a .json.gz
+		// name) are stored decompressed as .json
+		gzipped := fh.Header.Get("Content-Encoding") == "gzip" || strings.HasSuffix(name, ".json.gz")
+		if gzipped {
+			name = strings.TrimSuffix(name, ".gz")
+		}
+
+		// Land the upload in a hidden temp file first so its content hash can
+		// be checked against what's already stored before it gets a name.
+		tmp, err := os.CreateTemp(reportsDir, ".upload-*")
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		tmpName := tmp.Name()
+		tmp.Close()
+		defer os.Remove(tmpName)
 
-		dst := filepath.Join(reportsDir, name)
-		if err := c.SaveFile(fh, dst); err != nil {
+		if gzipped {
+			err = saveGunzipped(fh, tmpName)
+		} else {
+			err = c.SaveFile(fh, tmpName)
+		}
+		if err != nil {
+			status := fiber.StatusInternalServerError
+			if errors.Is(err, errBadUpload) {
+				status = fiber.StatusBadRequest
+			}
+			return c.Status(status).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		sum, size, err := fileSHA256(tmpName)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		// A retried upload whose first attempt actually landed has the same
+		// bytes as an existing report: keep the original, don't add a copy.
+		uploadMu.Lock()
+		defer uploadMu.Unlock()
+		if dup, err := findDuplicateReport(reportsDir, size, sum); err == nil && dup != "" {
+			webMetrics.duplicates.Add(1)
+			return c.JSON(fiber.Map{
+				"saved":     false,
+				"duplicate": true,
+				"path":      filepath.Join(reportsDir, filepath.FromSlash(dup)),
+				"public":    "/reports/" + dup,
+				"sha256":    sum,
+			})
+		}
+
+		// REPORTS_PARTITION=date files uploads under the server's receive date
+		if sub := reportSubdir(time.Now()); sub != "" {
+			if err := os.MkdirAll(filepath.Join(reportsDir, sub), 0755); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": err.Error(),
+				})
+			}
+			name = path.Join(sub, name)
+		}
+		dst := filepath.Join(reportsDir, filepath.FromSlash(name))
+		if err := os.Rename(tmpName, dst); err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": err.Error(),
 			})
 		}
+		webMetrics.uploads.Add(1)
 		return c.JSON(fiber.Map{
-			"saved":  true,
-			"path":   dst,
-			"public": fmt.Sprintf("/reports/%s", name),
+			"saved":     true,
+			"duplicate": false,
+			"path":      dst,
+			"public":    fmt.Sprintf("/reports/%s", name),
+			"sha256":    sum,
 		})
 	})
 
+	// Machine-readable listing (must be registered before the static mount)
+	app.Get("/reports/index.json", func(c *fiber.Ctx) error {
+		list, err := listReports(reportsDir)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(list)
+	})
+
+	// Most recent report for one machine → redirect to its /reports/ URL
+	app.Get("/reports/machine/:id/latest", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+		name, skipped, err := latestReportForMachine(reportsDir, id)
+		if skipped > 0 {
+			c.Set("X-Reports-Skipped", strconv.Itoa(skipped))
+		}
+		if err != nil {
+			status := fiber.StatusInternalServerError
+			if errors.Is(err, os.ErrNotExist) {
+				status = fiber.StatusNotFound
+			}
+			return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Redirect("/reports/"+escapeReportPath(name), fiber.StatusFound)
+	})
+
 	// Always expose /reports (directory browsing on)
 	app.Static("/reports", reportsDir, fiber.Static{
 		Browse: true,
 	})
 }
+
+// uploadMu serializes the duplicate check and the final rename, so two
+// identical uploads arriving together can't both be stored.
+var uploadMu sync.Mutex
+
+// fileSHA256 returns the hex SHA-256 and size of the file at path.
+func fileSHA256(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// findDuplicateReport returns the name (as listed by listReports) of a
+// stored report under dir with the given size and SHA-256, or "" if there is
+// none. Only same-size files are hashed, so the common no-duplicate case
+// costs a directory listing.
+func findDuplicateReport(dir string, size int64, sum string) (string, error) {
+	list, err := listReports(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, r := range list {
+		if r.Bytes != size {
+			continue
+		}
+		if other, _, err := fileSHA256(filepath.Join(dir, filepath.FromSlash(r.Name))); err == nil && other == sum {
+			return r.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// reportSubdir is the subdirectory of reports/ new uploads go to: the UTC
+// receive date (YYYY-MM-DD) with REPORTS_PARTITION=date, otherwise "" (flat).
+func reportSubdir(now time.Time) string {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("REPORTS_PARTITION")), "date") {
+		return now.UTC().Format("2006-01-02")
+	}
+	return ""
+}
+
+// escapeReportPath URL-escapes each segment of a slash-separated report name.
+func escapeReportPath(name string) string {
+	parts := strings.Split(name, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+// latestReportForMachine returns the name of the report in dir whose
+// machine_id is id and whose ended_at is latest. Files that aren't valid
+// telemetry JSON are skipped and counted. A wrapped os.ErrNotExist means no
+// report matched.
+func latestReportForMachine(dir, id string) (name string, skipped int, err error) {
+	list, err := listReports(dir)
+	if err != nil {
+		return "", 0, err
+	}
+	var best time.Time
+	for _, r := range list {
+		b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(r.Name)))
+		if err != nil {
+			skipped++
+			continue
+		}
+		var head struct {
+			MachineID string    `json:"machine_id"`
+			EndedAt   time.Time `json:"ended_at"`
+		}
+		if err := json.Unmarshal(b, &head); err != nil {
+			skipped++
+			continue
+		}
+		if head.MachineID != id {
+			continue
+		}
+		if name == "" || head.EndedAt.After(best) {
+			name, best = r.Name, head.EndedAt
+		}
+	}
+	if name == "" {
+		return "", skipped, fmt.Errorf("no report for machine %q: %w", id, os.ErrNotExist)
+	}
+	return name, skipped, nil
+}
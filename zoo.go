@@ -0,0 +1,434 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/openfluke/paragon/v3"
+)
+
+// runManageModelsMenu offers basic zoo management: delete a model (keeping
+// manifest/status in sync), reset it to fresh weights from its manifest spec,
+// warm-start it from another model's weights, migrate files to the current
+// paragon format, or check that a model survives save/load unchanged.
+func runManageModelsMenu() {
+	modelDir := ModelsDir()
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Println("\nManage models:")
+	fmt.Println("1) Delete a model")
+	fmt.Println("2) Reset a model to fresh init (from manifest spec)")
+	fmt.Println("3) Purge telemetry downloads and local reports (models_remote, reports_local)")
+	fmt.Println("4) Warm-start a model from another model's matching layers")
+	fmt.Println("5) Migrate a model to the current paragon format (keeps a .bak)")
+	fmt.Println("6) Migrate all models to the current paragon format")
+	fmt.Println("7) Verify a model round-trips through save/load unchanged")
+	fmt.Println("0) Back")
+	fmt.Print("Select: ")
+	modeRaw, _ := reader.ReadString('\n')
+	mode := strings.TrimSpace(modeRaw)
+	switch mode {
+	case "0":
+		return
+	case "1", "2", "4", "5", "7":
+	case "3":
+		fmt.Print("Really PURGE models_remote and reports_local? Type 'yes' to confirm: ")
+		confirm, _ := reader.ReadString('\n')
+		if strings.TrimSpace(confirm) != "yes" {
+			fmt.Println("Cancelled.")
+			return
+		}
+		purgeTelemetryData(reader)
+		return
+	case "6":
+		migrateAllModels(modelDir)
+		return
+	default:
+		fmt.Println("❌ Invalid choice")
+		return
+	}
+
+	models, _ := listModels(modelDir)
+	if len(models) == 0 {
+		fmt.Println("❌ No models found in public/models/")
+		return
+	}
+
+	status := loadModelStatus()
+	fmt.Println("\nAvailable models:")
+	for i, m := range models {
+		fmt.Printf("%d) %s%s %s\n", i+1, m, modelChoiceLabel(modelDir, m), modelStatusLabel(status, m))
+	}
+	fmt.Println("0) Back")
+	fmt.Print("Select model: ")
+	choiceRaw, _ := reader.ReadString('\n')
+	choice := strings.TrimSpace(choiceRaw)
+	if choice == "0" {
+		return
+	}
+	idx, err := strconv.Atoi(choice)
+	if err != nil || idx < 1 || idx > len(models) {
+		fmt.Println("❌ Invalid choice")
+		return
+	}
+	name := models[idx-1]
+
+	if mode == "7" {
+		ok, err := verifyRoundTrip(filepath.Join(modelDir, name))
+		switch {
+		case err != nil:
+			fmt.Println("❌", err)
+		case ok:
+			fmt.Printf("✅ %s round-trips bit-identically\n", name)
+		default:
+			fmt.Printf("❌ %s changed across save/load — serialization is lossy\n", name)
+		}
+		return
+	}
+
+	source := ""
+	if mode == "4" {
+		fmt.Print("Select source model to copy weights from: ")
+		srcRaw, _ := reader.ReadString('\n')
+		sidx, err := strconv.Atoi(strings.TrimSpace(srcRaw))
+		if err != nil || sidx < 1 || sidx > len(models) || sidx == idx {
+			fmt.Println("❌ Invalid choice")
+			return
+		}
+		source = models[sidx-1]
+	}
+
+	verb := map[string]string{
+		"1": "DELETE",
+		"2": "RESET (overwrite weights of)",
+		"4": "WARM-START (overwrite weights of)",
+		"5": "MIGRATE (rewrite, keeping a .bak of)",
+	}[mode]
+	fmt.Printf("Really %s %s? Type 'yes' to confirm: ", verb, name)
+	confirm, _ := reader.ReadString('\n')
+	if strings.TrimSpace(confirm) != "yes" {
+		fmt.Println("Cancelled.")
+		return
+	}
+
+	switch mode {
+	case "1":
+		err = deleteModel(modelDir, name)
+	case "2":
+		err = resetModel(modelDir, name)
+	case "4":
+		err = warmStartModel(modelDir, name, source)
+	case "5":
+		err = migrateModel(filepath.Join(modelDir, name))
+	}
+	if err != nil {
+		fmt.Println("❌", err)
+	}
+}
+
+// deleteModel removes the model file and drops it from manifest.json and status.json.
+func deleteModel(modelDir, name string) error {
+	path := filepath.Join(modelDir, name)
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("delete %s: %w", name, err)
+	}
+	fmt.Printf("🗑  Deleted %s\n", path)
+
+	manPath := filepath.Join(modelDir, "manifest.json")
+	specs, err := readManifest(manPath)
+	if err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+	kept := specs[:0]
+	for _, s := range specs {
+		if filepath.ToSlash(s.Filename) != name {
+			kept = append(kept, s)
+		}
+	}
+	if len(kept) != len(specs) {
+		if err := writeJSON(manPath, kept); err != nil {
+			return fmt.Errorf("manifest write: %w", err)
+		}
+		fmt.Printf("📜 manifest updated → %s\n", manPath)
+	}
+
+	forgetModelStatus(name)
+	return nil
+}
+
+// resetModel rebuilds name from its manifest spec (same path createModelZoo
+// uses) and overwrites the file with fresh random weights.
+func resetModel(modelDir, name string) error {
+	return reinitModel(modelDir, name, "")
+}
+
+// warmStartModel re-initializes name from its manifest spec like resetModel,
+// then copies in source's weights for the layers that match (see warmStart).
+func warmStartModel(modelDir, name, source string) error {
+	return reinitModel(modelDir, name, filepath.Join(modelDir, source))
+}
+
+// reinitModel rebuilds name from its manifest spec, warm-started from
+// sourcePath when set, and saves it over the old weights.
+func reinitModel(modelDir, name, sourcePath string) error {
+	manPath := filepath.Join(modelDir, "manifest.json")
+	specs, err := readManifest(manPath)
+	if err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+	for i, spec := range specs {
+		if filepath.ToSlash(spec.Filename) != name {
+			continue
+		}
+		var nn *paragon.Network[float32]
+		if sourcePath != "" {
+			nn, err = warmStart(spec, sourcePath)
+		} else {
+			nn, err = buildSpecNetwork(spec)
+		}
+		if err != nil {
+			return fmt.Errorf("%s init failed: %w", spec.ID, err)
+		}
+		outPath := filepath.Join(modelDir, name)
+		if err := saveModel(nn, outPath); err != nil {
+			return fmt.Errorf("%s save failed: %w", spec.ID, err)
+		}
+		if fi, err := os.Stat(outPath); err == nil {
+			specs[i].Bytes = fi.Size()
+		}
+		specs[i].Params = countParams(nn)
+		if err := writeJSON(manPath, specs); err != nil {
+			return fmt.Errorf("manifest write: %w", err)
+		}
+		forgetModelStatus(name)
+		if sourcePath != "" {
+			fmt.Printf("🔥 %s warm-started from %s → %s\n", spec.ID, filepath.Base(sourcePath), outPath)
+		} else {
+			fmt.Printf("♻️  %s reset to fresh init → %s\n", spec.ID, outPath)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s has no manifest spec; cannot rebuild it", name)
+}
+
+// verifyRoundTrip loads the model at modelPath, saves it to a temp file,
+// loads that back and checks that the forward outputs on the fixed digit
+// samples are bit-identical. Divergent digits are printed; the bool is false
+// if any output changed.
+func verifyRoundTrip(modelPath string) (bool, error) {
+	images, labels, err := getMNIST()
+	if err != nil {
+		return false, fmt.Errorf("load mnist: %w", err)
+	}
+	sel, err := sampleIndexPerDigit(labels)
+	if err != nil {
+		return false, err
+	}
+
+	before, err := loadFloat32Model(modelPath)
+	if err != nil {
+		return false, err
+	}
+	tmp, err := os.CreateTemp("", "roundtrip-*.json")
+	if err != nil {
+		return false, err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+	if err := saveModel(before, tmp.Name()); err != nil {
+		return false, fmt.Errorf("save: %w", err)
+	}
+	after, err := loadFloat32Model(tmp.Name())
+	if err != nil {
+		return false, fmt.Errorf("reload: %w", err)
+	}
+	before.WebGPUNative, after.WebGPUNative = false, false
+
+	if pb, pa := countParams(before), countParams(after); pb != pa {
+		fmt.Printf("   params: %d before, %d after\n", pb, pa)
+		return false, nil
+	}
+	same := true
+	for d := 0; d <= 9; d++ {
+		idx, ok := sel[d]
+		if !ok {
+			continue
+		}
+		before.Forward(images[idx])
+		outB := before.ExtractOutput()
+		after.Forward(images[idx])
+		outA := after.ExtractOutput()
+		if len(outB) != len(outA) {
+			fmt.Printf("   digit %d: output length %d → %d\n", d, len(outB), len(outA))
+			same = false
+			continue
+		}
+		var diff int
+		for i := range outB {
+			if math.Float64bits(outB[i]) != math.Float64bits(outA[i]) {
+				diff++
+			}
+		}
+		if diff > 0 {
+			mx, _ := driftMaxAndMAE(outB, outA)
+			fmt.Printf("   digit %d (idx %d): %d/%d outputs differ, max |Δ| %.3e\n", d, idx, diff, len(outB), mx)
+			same = false
+		}
+	}
+	return same, nil
+}
+
+// migrateModel upgrades the model file at path to the current paragon format:
+// it is loaded, rebuilt via NewNetwork + Marshal/Unmarshal (rebuildFloat32)
+// and saved back over path. The original is kept as path.bak (an existing
+// .bak from an earlier run is left alone), and the new file is written
+// beside it and renamed in, so a failed save never leaves a truncated model.
+// Weights and activations are unchanged.
+func migrateModel(path string) error {
+	if activationOverride() != nil {
+		return fmt.Errorf("migrate %s: --activations is set and would be baked into the file", filepath.Base(path))
+	}
+	loaded, err := paragon.LoadNamedNetworkFromJSONFile(path)
+	if err != nil {
+		return fmt.Errorf("migrate %s: load failed: %w", filepath.Base(path), err)
+	}
+	nn, err := rebuildFloat32(loaded)
+	if err != nil {
+		return fmt.Errorf("migrate %s: %w", filepath.Base(path), err)
+	}
+
+	// An existing .bak is from an earlier migration and holds the original;
+	// never overwrite it with an already-migrated file.
+	bak, err := os.OpenFile(path+".bak", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	switch {
+	case errors.Is(err, os.ErrExist):
+	case err != nil:
+		return fmt.Errorf("migrate %s: backup: %w", filepath.Base(path), err)
+	default:
+		err = copyFileTo(bak, path)
+		if cerr := bak.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(bak.Name())
+			return fmt.Errorf("migrate %s: backup: %w", filepath.Base(path), err)
+		}
+	}
+
+	tmp := path + ".migrate"
+	if err := saveModel(nn, tmp); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("migrate %s: save failed: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("migrate %s: %w", filepath.Base(path), err)
+	}
+	fmt.Printf("🔁 Migrated %s (backup → %s.bak)\n", path, filepath.Base(path))
+	return nil
+}
+
+// migrateAllModels runs migrateModel over every model in modelDir and reports
+// how many were upgraded.
+func migrateAllModels(modelDir string) {
+	models, err := listModels(modelDir)
+	if err != nil || len(models) == 0 {
+		fmt.Println("❌ No models found in public/models/")
+		return
+	}
+	var failed int
+	for _, name := range models {
+		if err := migrateModel(filepath.Join(modelDir, name)); err != nil {
+			fmt.Println("❌", err)
+			failed++
+		}
+	}
+	fmt.Printf("✅ Migrated %d/%d models\n", len(models)-failed, len(models))
+}
+
+// purgeTelemetryData empties the directories the telemetry pipeline fills on
+// clients: downloaded models (models_remote) and local report copies
+// (reports_local). The directories themselves are kept. Reports that were
+// never uploaded (no .uploaded marker) are kept too, unless a second prompt
+// on reader confirms deleting them.
+func purgeTelemetryData(reader *bufio.Reader) {
+	localDir := MustPublicPath("reports_local")
+	keep := map[string]bool{}
+	pending, _ := pendingReports(localDir)
+	for _, name := range pending {
+		keep[name] = true
+		keep[strings.TrimSuffix(name, ".json")+runContextSuffix] = true
+	}
+
+	for _, sub := range []string{"models_remote", "reports_local"} {
+		dir := MustPublicPath(sub)
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			fmt.Printf("ℹ️  %s: nothing to purge\n", dir)
+			continue
+		}
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", dir, err)
+			continue
+		}
+		var files int
+		var freed int64
+		for _, e := range entries {
+			if dir == localDir && keep[e.Name()] {
+				continue
+			}
+			p := filepath.Join(dir, e.Name())
+			files += countFiles(p, &freed)
+			if err := os.RemoveAll(p); err != nil {
+				fmt.Printf("⚠️  %s: %v\n", p, err)
+			}
+		}
+		fmt.Printf("🧹 %s: removed %d file(s), %.1f MB freed\n", dir, files, float64(freed)/1e6)
+	}
+
+	if len(pending) == 0 {
+		return
+	}
+	fmt.Printf("\n⚠️  %d report(s) in %s were never uploaded and were kept:\n", len(pending), localDir)
+	for _, name := range pending {
+		fmt.Println("   " + name)
+	}
+	fmt.Printf("Option %s (re-upload pending telemetry reports) can still send them.\n", reuploadCommandCode)
+	fmt.Print("Delete them anyway? Type 'yes' to confirm: ")
+	confirm, _ := reader.ReadString('\n')
+	if strings.TrimSpace(confirm) != "yes" {
+		fmt.Println("Kept.")
+		return
+	}
+	var removed int
+	for _, name := range pending {
+		for _, fn := range []string{name, strings.TrimSuffix(name, ".json") + runContextSuffix} {
+			p := filepath.Join(localDir, fn)
+			if err := os.Remove(p); err == nil {
+				removed++
+			} else if !os.IsNotExist(err) {
+				fmt.Printf("⚠️  %s: %v\n", p, err)
+			}
+		}
+	}
+	fmt.Printf("🧹 %s: removed %d more file(s)\n", localDir, removed)
+}
+
+// countFiles counts regular files under path and adds their sizes to bytes.
+func countFiles(path string, bytes *int64) int {
+	n := 0
+	_ = filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			n++
+			*bytes += info.Size()
+		}
+		return nil
+	})
+	return n
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMigrateKeepsFirstBackup migrates a model twice and checks the .bak
+// still holds the file as it was before the first migration.
+func TestMigrateKeepsFirstBackup(t *testing.T) {
+	nn, err := buildSpecNetwork(ModelSpec{ID: "T", Layers: []string{"784", "8", "10"}})
+	if err != nil {
+		t.Fatalf("buildSpecNetwork: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "mnist_T.json")
+	if err := nn.SaveJSON(path); err != nil {
+		t.Fatalf("SaveJSON: %v", err)
+	}
+	// a marker the migration's re-save drops, so the two can be told apart
+	orig, _ := os.ReadFile(path)
+	orig = append(orig, '\n', '\n')
+	if err := os.WriteFile(path, orig, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := migrateModel(path); err != nil {
+			t.Fatalf("migration %d: %v", i+1, err)
+		}
+	}
+	bak, err := os.ReadFile(path + ".bak")
+	if err != nil {
+		t.Fatalf("backup: %v", err)
+	}
+	if !bytes.Equal(bak, orig) {
+		t.Fatal("the second migration overwrote the original backup")
+	}
+}